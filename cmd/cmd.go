@@ -14,36 +14,72 @@ import (
 // Config not found error
 var ErrConfigNotFound = errors.New("not found config")
 
-// Command-line arguments
-var (
-	_logo          = flag.String("logo", "auto", "Selects which logo is displayed")
-	_userline      = flag.Bool("userline", true, "Display username and hostname")
-	_userunderline = flag.Bool("userunderline", true, "Display fancy line of - under userline")
-	_os            = flag.Bool("os", true, "Display host os and architecture")
-	_kernel        = flag.Bool("kernel", true, "Display system kernel type and version")
-	_uptime        = flag.Bool("uptime", true, "Display system uptime")
-	_shell         = flag.Bool("shell", true, "Display current shell")
-	_resolution    = flag.Bool("resolution", true, "Display screen resolution")
-	_cpu           = flag.Bool("cpu", true, "Display CPU model")
-	_gpu           = flag.Bool("gpu", true, "Display GPU manufacturer and model")
-	_memory        = flag.Bool("memory", true, "Display used and total memory in megabytes")
-	_localip       = flag.Bool("localip", true, "Display local IP")
-	_remoteip      = flag.Bool("remoteip", true, "Display remote IP")
-	_colors        = flag.Bool("colors", true, "Display colors")
-)
+// Returns command-line flag set, flags are named after config options they
+// override
+func newFlagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("barkfetch", flag.ContinueOnError)
+
+	flags.String("logo", "auto", "Selects which logo is displayed")
+	flags.Bool("userline", true, "Display username and hostname")
+	flags.Bool("userline_name", false, "Display full name of user after userline")
+	flags.Bool("userline_sudo", false, "Display real user after userline under sudo")
+	flags.String("title", "{user}@{host}", "Title template: {user}, {host}, {short}, {fqdn}, {domain}, {ip}, {name}, {real_user}")
+	flags.Bool("userunderline", true, "Display fancy line of - under userline")
+	flags.Bool("os", true, "Display host os and architecture")
+	flags.Bool("kernel", true, "Display system kernel type and version")
+	flags.Bool("uptime", true, "Display system uptime")
+	flags.Bool("shell", true, "Display current shell")
+	flags.Bool("resolution", true, "Display screen resolution")
+	flags.Bool("cpu", true, "Display CPU model")
+	flags.Bool("gpu", true, "Display GPU manufacturer and model")
+	flags.Bool("memory", true, "Display used and total memory in megabytes")
+	flags.Bool("swap", true, "Display used and total swap in megabytes")
+	flags.Bool("disk", true, "Display used and total space of disk_mounts")
+	flags.Bool("battery", true, "Display battery charge")
+	flags.Bool("localip", true, "Display local IP")
+	flags.Bool("remoteip", true, "Display remote IP")
+	flags.Bool("colors", true, "Display colors")
+	flags.String("logo_size", "auto", "Selects logo size: auto, small, large or none")
+	flags.String("logo_position", "left", "Selects logo position: left, right, top, bottom or hidden")
+	flags.Int("logo_padding", 0, "Count of columns before logo and info")
+	flags.Int("logo_gap", 1, "Count of columns or lines between logo and info")
+	flags.String("logo_image", "", "Draw PNG or JPEG image as logo, if terminal supports images")
+	flags.Int("logo_image_width", 0, "Width of image logo in columns, 0 is image width up to 30")
+	flags.String("image-backend", "auto", "Selects image protocol: auto, kitty, iterm2 or sixel")
+	flags.String("disk_mounts", "/", "Comma-separated mount points of disk module")
+	flags.String("bar_mode", "off", "Show percentage bars: off, bar or bar+text")
+	flags.Int("bar_width", 10, "Width of percentage bars in cells")
+	flags.Var(new(redactFlag), "redact", "Mask username, hostname, IPs, MACs and serials: mask (default) or pseudonym")
+	flags.String("accent", "", "Override accent color, e.g. c4, blue or #5e81ac")
+	flags.String("theme", "default", "Selects theme by name or path")
+	flags.String("template", "", "Render output with Go text/template file")
+	flags.String("format", "ansi", "Selects output format: ansi, html, svg, markdown or table")
+	flags.String("snapshot", "", "Record files and commands output probes read to tar file, redacted with --redact")
+	flags.String("replay", "", "Render fetch from snapshot tar file")
+	flags.Bool("list-logos", false, "List every available logo and where it comes from")
+	flags.Bool("debug", false, "Print debug messages, like why logo was chosen")
+	flags.Bool("no-cache", false, "Do not use on-disk cache of slow probes")
+	flags.Bool("refresh", false, "Recompute cached probes and update cache")
+
+	return flags
+}
 
-// Helper function, returns true if flag was given at command-line
-func isFlagPassed(name string) bool {
-	found := false
-	flag.Visit(func(f *flag.Flag) {
-		if f.Name == name {
-			found = true
-		}
-	})
+// redactFlag is a --redact flag, which can be given without value like
+// boolean flag, or with redact mode like --redact=pseudonym
+type redactFlag string
+
+// String returns redact mode
+func (f *redactFlag) String() string { return string(*f) }
 
-	return found
+// Set sets redact mode, "true" if flag is given without value
+func (f *redactFlag) Set(value string) error {
+	*f = redactFlag(value)
+	return nil
 }
 
+// IsBoolFlag allows flag without value
+func (f *redactFlag) IsBoolFlag() bool { return true }
+
 // Converts true to "true" and false to "false"
 func boolToString(input bool) string {
 	if input {
@@ -53,8 +89,37 @@ func boolToString(input bool) string {
 	return "false"
 }
 
-// Load config and return map of options
-func loadConfig() (map[string]string, error) {
+// Parses command-line arguments and returns options passed there
+func parseFlags(args []string) (map[string]string, error) {
+	flags := newFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return map[string]string{}, err
+	}
+
+	options := make(map[string]string)
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "no-cache":
+			options["cache"] = boolToString(f.Value.String() != "true")
+
+		case "image-backend":
+			options["image_backend"] = f.Value.String()
+
+		case "format":
+			options["output_format"] = f.Value.String()
+
+		default:
+			options[f.Name] = f.Value.String()
+		}
+	})
+
+	return options, nil
+}
+
+// Load config, override it with options from command-line and return map
+// of options
+func loadConfig(overrides map[string]string) (map[string]string, error) {
 	f, err := os.Open("./barkfetch.config")
 	if err == nil {
 		goto configChosed
@@ -83,100 +148,191 @@ configChosed:
 	contents := string(raw)
 	config := parseConfig(contents)
 
-	if isFlagPassed("logo") {
-		config["logo"] = *_logo
+	for key, value := range overrides {
+		config[key] = value
 	}
 
-	if isFlagPassed("userline") {
-		config["userline"] = boolToString(*_userline)
-	}
+	return config, nil
+}
 
-	if isFlagPassed("userunderline") {
-		config["userunderline"] = boolToString(*_userunderline)
-	}
+// Parse simple config
+func parseConfig(config string) map[string]string {
+	options := make(map[string]string)
+
+	for _, line := range strings.Split(config, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+
+		if line[0] == '#' {
+			continue
+		}
 
-	if isFlagPassed("os") {
-		config["os"] = boolToString(*_os)
+		// split on first "=" only, as value templates may contain it
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		options[key] = value
 	}
 
-	if isFlagPassed("kernel") {
-		config["kernel"] = boolToString(*_kernel)
+	return options
+}
+
+// Run cmd-related stuff and return non-nil error if something is wrong
+func Run() error {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "logo":
+			return runLogo(os.Args[2:])
+
+		case "serve":
+			return runServe(os.Args[2:])
+		}
 	}
 
-	if isFlagPassed("uptime") {
-		config["uptime"] = boolToString(*_uptime)
+	overrides, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
 	}
 
-	if isFlagPassed("shell") {
-		config["shell"] = boolToString(*_shell)
+	if err != nil {
+		return err
 	}
 
-	if isFlagPassed("resolution") {
-		config["resolution"] = boolToString(*_resolution)
+	if overrides["list-logos"] == "true" {
+		listLogos()
+		return nil
 	}
 
-	if isFlagPassed("cpu") {
-		config["cpu"] = boolToString(*_cpu)
+	config, err := loadConfig(overrides)
+	if err != nil {
+		return err
 	}
 
-	if isFlagPassed("gpu") {
-		config["gpu"] = boolToString(*_gpu)
+	renderer := info.NewRenderer()
+
+	if config["debug"] == "true" {
+		renderer.Debug = os.Stderr
 	}
 
-	if isFlagPassed("memory") {
-		config["memory"] = boolToString(*_memory)
+	// cache is bypassed, so every probe reads recorded or real system
+	if path := config["replay"]; path != "" {
+		snapshot, err := readSnapshot(path)
+		if err != nil {
+			return err
+		}
+
+		renderer.System = snapshot.System()
+		config["cache"] = "false"
 	}
 
-	if isFlagPassed("localip") {
-		config["localip"] = boolToString(*_localip)
+	var snapshot *info.Snapshot
+
+	if path := config["snapshot"]; path != "" {
+		snapshot = info.NewSnapshot()
+		renderer.System = renderer.System.Record(snapshot)
+		config["cache"] = "false"
 	}
 
-	if isFlagPassed("remoteip") {
-		config["remoteip"] = boolToString(*_remoteip)
+	sysinfo, err := render(renderer, config)
+	if err != nil {
+		return err
 	}
 
-	if isFlagPassed("colors") {
-		config["colors"] = boolToString(*_colors)
+	fmt.Print(sysinfo)
+
+	if snapshot != nil {
+		// snapshots are made for bug reports, so they are redacted like
+		// output
+		if err := snapshot.Redact(config); err != nil {
+			return err
+		}
+
+		return writeSnapshot(config["snapshot"], snapshot)
 	}
 
-	return config, nil
+	return nil
 }
 
-// Parse simple config
-func parseConfig(config string) map[string]string {
-	options := make(map[string]string)
-
-	for _, line := range strings.Split(config, "\n") {
-		if len(line) == 0 {
-			continue
+// Prints every available logo and directory it's found in
+func listLogos() {
+	for _, entry := range info.ListLogos(info.DefaultLogoDirs()) {
+		path := entry.Path
+		if entry.Shadowed {
+			path += " (shadowed)"
 		}
 
-		if line[0] == '#' {
-			continue
-		}
+		fmt.Printf("%-24v %v\n", entry.Name, path)
+	}
+}
 
-		kv := strings.Split(line, "=")
+// Renders fetch with template from config, or in standard layout, in
+// output format from config
+func render(renderer *info.Renderer, config map[string]string) (string, error) {
+	switch format := config["output_format"]; format {
+	case "", "ansi":
+		return renderANSI(renderer, config)
 
-		if len(kv) < 2 || len(kv) > 2 {
-			continue
+	case "html":
+		return renderer.RenderHTML(config)
+
+	case "svg":
+		return renderer.RenderSVG(config)
+
+	case "markdown":
+		return renderer.RenderMarkdown(config)
+
+	case "table":
+		return renderer.RenderTable(config)
+
+	default:
+		return "", fmt.Errorf("%w: output_format=%v", info.ErrInvalidOption, format)
+	}
+}
+
+// Renders fetch for terminal
+func renderANSI(renderer *info.Renderer, config map[string]string) (string, error) {
+	if path := config["template"]; path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
 		}
 
-		options[kv[0]] = kv[1]
+		return renderer.RenderTemplate(config, string(raw))
 	}
 
-	return options
+	sysinfo, err := renderer.Render(config)
+	if err != nil {
+		return "", err
+	}
+
+	return sysinfo + "\n", nil
 }
 
-// Run cmd-related stuff and return non-nil error if something is wrong
-func Run() error {
-	flag.Parse()
-	config, err := loadConfig()
+// Reads snapshot from tar file
+func readSnapshot(path string) (*info.Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return info.ReadSnapshot(f)
+}
+
+// Writes snapshot to tar file
+func writeSnapshot(path string, snapshot *info.Snapshot) error {
+	f, err := os.Create(path)
 	if err != nil {
 		return err
 	}
 
-	sysinfo := info.GetInfoString(config)
-	fmt.Println(sysinfo)
+	if err := snapshot.WriteTar(f); err != nil {
+		f.Close()
+		return err
+	}
 
-	return nil
+	return f.Close()
 }
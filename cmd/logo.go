@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+
+	"github.com/xbt573/barkfetch/info"
+
+	// Decoders of images logo convert reads
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Errors of "logo" subcommands
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrNoLogos        = errors.New("no logo files given")
+	ErrLintFailed     = errors.New("logo lint found problems")
+	ErrNoImage        = errors.New("expected one image file")
+)
+
+// Runs "barkfetch logo <command>" subcommands
+func runLogo(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: logo, expected lint or convert", ErrUnknownCommand)
+	}
+
+	switch args[0] {
+	case "lint":
+		return lintLogos(args[1:])
+
+	case "convert":
+		return convertLogo(args[1:])
+	}
+
+	return fmt.Errorf("%w: logo %v", ErrUnknownCommand, args[0])
+}
+
+// Lints logo files and prints found problems as "path:line: message"
+func lintLogos(paths []string) error {
+	if len(paths) == 0 {
+		return ErrNoLogos
+	}
+
+	failed := false
+
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			// path is printed once, as "path: error"
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				err = pathErr.Err
+			}
+
+			fmt.Fprintf(os.Stderr, "%v: %v\n", path, err)
+			failed = true
+			continue
+		}
+
+		for _, problem := range info.LintLogo(string(raw)) {
+			fmt.Printf("%v:%v: %v\n", path, problem.Line, problem.Message)
+			failed = true
+		}
+	}
+
+	if failed {
+		return ErrLintFailed
+	}
+
+	return nil
+}
+
+// Converts image into logo and prints it, or writes it to --output file
+func convertLogo(args []string) error {
+	flags := flag.NewFlagSet("barkfetch logo convert", flag.ContinueOnError)
+	width := flags.Int("width", 30, "Logo width in columns")
+	style := flags.String("style", "halfblock", "Logo style: ascii, halfblock or braille")
+	colors := flags.String("colors", "16", "Logo colors: 16 (c0-c15) or truecolor")
+	output := flags.String("output", "", "Write logo to file instead of standard output")
+
+	// flags may go after image path, so parse until every argument is read
+	paths := []string{}
+	for {
+		err := flags.Parse(args)
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if flags.NArg() == 0 {
+			break
+		}
+
+		paths = append(paths, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(paths) != 1 {
+		return ErrNoImage
+	}
+
+	depth := info.Depth16
+	switch *colors {
+	case "16":
+
+	case "truecolor":
+		depth = info.DepthTrueColor
+
+	default:
+		return fmt.Errorf("%w: colors=%v", info.ErrInvalidOption, *colors)
+	}
+
+	file, err := os.Open(paths[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("%v: %w", paths[0], err)
+	}
+
+	logo, err := info.ConvertLogo(img, *width, *style, depth)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		// same as printed logo, ending with newline
+		return os.WriteFile(*output, []byte(logo+"\n"), 0o644)
+	}
+
+	fmt.Println(logo)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Writes 2x2 red PNG image with transparent corner to directory, returns
+// it's path
+func writeTestImage(t *testing.T, dir string) string {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	img.Set(1, 0, color.RGBA{0xff, 0, 0, 0xff})
+	img.Set(0, 1, color.RGBA{0xff, 0, 0, 0xff})
+
+	path := filepath.Join(dir, "logo.png")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// Returns standard output of function
+func captureStdout(t *testing.T, function func() error) string {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		raw, _ := io.ReadAll(reader)
+		output <- string(raw)
+	}()
+
+	err = function()
+	writer.Close()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return <-output
+}
+
+func TestConvertLogoOutput(t *testing.T) {
+	dir := t.TempDir()
+	image := writeTestImage(t, dir)
+	path := filepath.Join(dir, "logo.txt")
+
+	for _, style := range []string{"ascii", "halfblock", "braille"} {
+		printed := captureStdout(t, func() error {
+			return convertLogo([]string{image, "--style", style, "--width", "2"})
+		})
+
+		if err := convertLogo([]string{image, "--style", style, "--width", "2", "--output", path}); err != nil {
+			t.Fatal(err)
+		}
+
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(written) != printed {
+			t.Errorf("%v: written %q, printed %q", style, written, printed)
+		}
+
+		if !strings.HasSuffix(printed, "${creset}\n") {
+			t.Errorf("%v: logo doesn't end with reset and newline: %q", style, printed)
+		}
+	}
+}
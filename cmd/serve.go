@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xbt573/barkfetch/info"
+)
+
+// No listen address given error
+var ErrNoListenAddress = errors.New("expected --listen or --metrics address")
+
+// Invalid query parameter error
+var ErrInvalidQuery = errors.New("invalid query parameter")
+
+// Content types of fetch endpoints, by path
+var fetchContentTypes = map[string]string{
+	"/json": "application/json; charset=utf-8",
+	"/text": "text/plain; charset=utf-8",
+	"/ansi": "text/plain; charset=utf-8",
+	"/html": "text/html; charset=utf-8",
+	"/svg":  "image/svg+xml",
+}
+
+// Runs "barkfetch serve", serving collected info over HTTP
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("barkfetch serve", flag.ContinueOnError)
+	listen := flags.String("listen", "", "Serve fetch on /json, /text, /ansi, /html and /svg at address, e.g. 127.0.0.1:8080")
+	metrics := flags.String("metrics", "", "Serve Prometheus metrics on /metrics at address, e.g. :9100")
+	cacheTTL := flags.Duration("cache-ttl", 5*time.Second, "Reuse responses of same request for duration, 0 disables")
+	debug := flags.Bool("debug", false, "Print debug messages")
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if *listen == "" && *metrics == "" {
+		return ErrNoListenAddress
+	}
+
+	config, err := loadConfig(map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	renderer := info.NewRenderer()
+
+	// output isn't drawn in terminal barkfetch runs in
+	renderer.TerminalWidth = 0
+	renderer.ImageBackend = ""
+
+	if *debug {
+		renderer.Debug = os.Stderr
+	}
+
+	// handlers of every address, metrics and fetch can share one
+	muxes := map[string]*http.ServeMux{}
+	mux := func(address string) *http.ServeMux {
+		if muxes[address] == nil {
+			muxes[address] = http.NewServeMux()
+		}
+
+		return muxes[address]
+	}
+
+	if *listen != "" {
+		fetch := &fetchHandler{
+			renderer: renderer,
+			config:   config,
+			cache:    responseCache{ttl: *cacheTTL, entries: map[string]cachedResponse{}},
+		}
+
+		for path := range fetchContentTypes {
+			mux(*listen).Handle(path, fetch)
+		}
+	}
+
+	if *metrics != "" {
+		mux(*metrics).HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", info.MetricsContentType)
+
+			if err := renderer.WriteMetrics(w, config); err != nil {
+				fmt.Fprintf(os.Stderr, "barkfetch: metrics: %v\n", err)
+			}
+		})
+	}
+
+	errs := make(chan error, len(muxes))
+
+	for address, handler := range muxes {
+		server := &http.Server{
+			Addr:              address,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() { errs <- server.ListenAndServe() }()
+	}
+
+	return <-errs
+}
+
+// fetchHandler serves fetch rendered like with GetInfoString: structured
+// info on /json, plain text on /text, colored text on /ansi, HTML page on
+// /html and SVG image on /svg. Modules can be chosen per request with query
+// parameters
+type fetchHandler struct {
+	renderer *info.Renderer
+	config   map[string]string
+	cache    responseCache
+}
+
+// ServeHTTP renders fetch of request path and query
+func (h *fetchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	options, err := queryOptions(h.config, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// same requests are same with any order of query parameters
+	key := r.URL.Path + "?" + r.URL.Query().Encode()
+
+	body, err := h.cache.get(key, func() (string, error) {
+		return h.render(r.URL.Path, options)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "barkfetch: serve %v: %v\n", r.URL, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", fetchContentTypes[r.URL.Path])
+	fmt.Fprint(w, body)
+}
+
+// Renders fetch for endpoint path
+func (h *fetchHandler) render(path string, options map[string]string) (string, error) {
+	switch path {
+	case "/json":
+		sysinfo, err := h.renderer.Collect(options)
+		if err != nil {
+			return "", err
+		}
+
+		raw, err := json.MarshalIndent(sysinfo, "", "  ")
+		if err != nil {
+			return "", err
+		}
+
+		return string(raw) + "\n", nil
+
+	case "/html":
+		return h.renderer.RenderHTML(options)
+
+	case "/svg":
+		return h.renderer.RenderSVG(options)
+	}
+
+	lines, err := h.renderer.RenderLines(options)
+	if err != nil {
+		return "", err
+	}
+
+	sysinfo := strings.Join(lines, "\n") + "\n"
+	if path == "/text" {
+		return info.StripEscapes(sysinfo), nil
+	}
+
+	return sysinfo, nil
+}
+
+// Returns config with modules overridden by query parameters: "modules" is
+// a comma-separated list of only enabled modules, and every module can be
+// enabled or disabled by it's name, e.g. ?modules=os,cpu&memory=true. Other
+// options can't be changed, so requests can't read files or disable redact
+func queryOptions(config map[string]string, query url.Values) (map[string]string, error) {
+	options := make(map[string]string, len(config))
+	for key, value := range config {
+		options[key] = value
+	}
+
+	known := map[string]bool{}
+	for _, module := range info.Modules() {
+		known[module] = true
+	}
+
+	if query.Has("modules") {
+		for module := range known {
+			options[module] = "false"
+		}
+
+		for _, module := range strings.Split(query.Get("modules"), ",") {
+			if module = strings.TrimSpace(module); module == "" {
+				continue
+			}
+
+			if !known[module] {
+				return options, fmt.Errorf("%w: unknown module %v", ErrInvalidQuery, module)
+			}
+
+			options[module] = "true"
+		}
+	}
+
+	for key := range query {
+		if key == "modules" {
+			continue
+		}
+
+		if !known[key] {
+			return options, fmt.Errorf("%w: %v", ErrInvalidQuery, key)
+		}
+
+		enabled, err := strconv.ParseBool(query.Get(key))
+		if err != nil {
+			return options, fmt.Errorf("%w: %v=%v", ErrInvalidQuery, key, query.Get(key))
+		}
+
+		options[key] = boolToString(enabled)
+	}
+
+	return options, nil
+}
+
+// responseCache keeps rendered responses for ttl, so frequent requests
+// don't probe system every time. Responses are rendered one at a time
+type responseCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedResponse
+}
+
+// cachedResponse is a rendered response and time it expires at
+type cachedResponse struct {
+	body    string
+	expires time.Time
+}
+
+// Returns cached response by key, or renders and caches it if it's
+// missing or expired
+func (c *responseCache) get(key string, render func() (string, error)) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+
+	if entry, exists := c.entries[key]; exists && now.Before(entry.expires) {
+		return entry.body, nil
+	}
+
+	body, err := render()
+	if err != nil || c.ttl <= 0 {
+		return body, err
+	}
+
+	for cachedKey, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, cachedKey)
+		}
+	}
+
+	c.entries[key] = cachedResponse{body: body, expires: now.Add(c.ttl)}
+
+	return body, nil
+}
@@ -0,0 +1,136 @@
+package info
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Percentage bar style, read from bar_* options
+type barStyle struct {
+	// mode is "off", "bar" or "bar+text"
+	mode string
+
+	// width is a count of bar cells, without brackets
+	width int
+
+	// fill and empty are characters of filled and empty cells
+	fill, empty string
+
+	// warn and crit are percentages bar is painted with warning and
+	// critical color from
+	warn, crit int
+}
+
+// Default bar style, bars are off
+var defaultBarStyle = barStyle{
+	mode:  "off",
+	width: 10,
+	fill:  "█",
+	empty: "░",
+	warn:  70,
+	crit:  90,
+}
+
+// Reads bar style from options
+func parseBarStyle(options map[string]string) (barStyle, error) {
+	style := defaultBarStyle
+
+	switch mode := options["bar_mode"]; mode {
+	case "":
+
+	case "off", "bar", "bar+text":
+		style.mode = mode
+
+	default:
+		return style, fmt.Errorf("%w: bar_mode=%v", ErrInvalidOption, mode)
+	}
+
+	if fill, exists := options["bar_fill"]; exists && fill != "" {
+		style.fill = fill
+	}
+
+	if empty, exists := options["bar_empty"]; exists && empty != "" {
+		style.empty = empty
+	}
+
+	for _, option := range []struct {
+		key   string
+		value *int
+	}{{"bar_width", &style.width}, {"bar_warn", &style.warn}, {"bar_crit", &style.crit}} {
+		value, exists := options[option.key]
+		if !exists || value == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			return style, fmt.Errorf("%w: %v=%v", ErrInvalidOption, option.key, value)
+		}
+
+		*option.value = number
+	}
+
+	return style, nil
+}
+
+// Returns bar like [████░░░░] filled by percent, filled cells are painted
+// with color
+func (style barStyle) render(theme Theme, color string, percent int) string {
+	filled := percent * style.width / 100
+
+	if filled < 0 {
+		filled = 0
+	}
+
+	if filled > style.width {
+		filled = style.width
+	}
+
+	fill := strings.Repeat(style.fill, filled)
+	if filled > 0 {
+		fill = theme.paint(color, fill)
+	}
+
+	return "[" + fill + strings.Repeat(style.empty, style.width-filled) + "]"
+}
+
+// Returns color of level by bar thresholds: good, warning or critical. For
+// battery level is it's discharge, as low charge is bad
+func (style barStyle) color(theme Theme, level int) string {
+	switch {
+	case level >= style.crit:
+		return theme.Crit
+
+	case level >= style.warn:
+		return theme.Warn
+	}
+
+	return theme.Good
+}
+
+// Returns value with bar of percent by bar mode, filled cells are painted
+// with color
+func (style barStyle) apply(theme Theme, value string, percent int, color string) string {
+	switch style.mode {
+	case "bar":
+		return style.render(theme, color, percent)
+
+	case "bar+text":
+		return style.render(theme, color, percent) + " " + value
+	}
+
+	return value
+}
+
+// Returns bar like [████░░░░] of width filled by percent
+func bar(percent, width int) string {
+	style := defaultBarStyle
+	style.width = width
+
+	if style.width < 0 {
+		style.width = 0
+	}
+
+	return style.render(Theme{}, "", percent)
+}
@@ -0,0 +1,180 @@
+package info
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Time-to-live of cached probes, zero means value lives until reboot
+var cacheTTLs = map[string]time.Duration{
+	"os":         0,
+	"kernel":     0,
+	"cpu":        0,
+	"gpu":        0,
+	"resolution": 10 * time.Minute,
+	"remoteip":   time.Hour,
+	"fqdn":       time.Hour,
+}
+
+// cacheEntry is a single cached probe value as stored on disk
+type cacheEntry struct {
+	// BootId is a boot ID of system, when value was probed
+	BootId string `json:"boot_id"`
+
+	// Time is a time, when value was probed
+	Time time.Time `json:"time"`
+
+	// Value is a probed value
+	Value json.RawMessage `json:"value"`
+}
+
+// cache is an on-disk cache of slow probes, keyed on boot ID
+type cache struct {
+	// dir is a directory where cache entries are stored
+	dir string
+
+	// bootId is a current boot ID, cache is disabled if empty
+	bootId string
+
+	// refresh forces probes to run and overwrite cached values
+	refresh bool
+}
+
+// Returns cache directory, like $XDG_CACHE_HOME/barkfetch or
+// ~/.cache/barkfetch, error if neither $XDG_CACHE_HOME nor $HOME is set
+func getCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "barkfetch"), nil
+}
+
+// Returns config directory, like $XDG_CONFIG_HOME/barkfetch or
+// ~/.config/barkfetch, error if neither $XDG_CONFIG_HOME nor $HOME is set
+func getConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "barkfetch"), nil
+}
+
+// Creates cache of boot from "cache" and "refresh" options, returns nil if
+// disabled
+func newCache(options map[string]string, bootId string) *cache {
+	if options["cache"] == "false" {
+		return nil
+	}
+
+	if bootId == "" {
+		return nil
+	}
+
+	// without home directory there is no place for cache
+	dir, err := getCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &cache{
+		dir:     dir,
+		bootId:  bootId,
+		refresh: options["refresh"] == "true",
+	}
+}
+
+// Reads entry by key, returns false if entry is missing or expired
+func (c *cache) load(key string, value any) bool {
+	raw, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return false
+	}
+
+	if entry.BootId != c.bootId {
+		return false
+	}
+
+	ttl := cacheTTLs[key]
+	if ttl > 0 && time.Since(entry.Time) > ttl {
+		return false
+	}
+
+	return json.Unmarshal(entry.Value, value) == nil
+}
+
+// Writes entry by key, errors are ignored as cache is optional
+func (c *cache) store(key string, value any) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	// failed probes are retried on next run instead of being cached
+	if probeFailed(raw) {
+		return
+	}
+
+	entry, err := json.Marshal(cacheEntry{
+		BootId: c.bootId,
+		Time:   time.Now(),
+		Value:  raw,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(entry)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+
+	os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+}
+
+// Returns true if JSON of probe value is a result of failed probe: "n/a",
+// empty string, empty list or null
+func probeFailed(raw []byte) bool {
+	switch string(raw) {
+	case `"n/a"`, `""`, `[]`, `null`:
+		return true
+	}
+
+	return false
+}
+
+// Returns cached probe value by key, or runs probe and caches it's result
+func cached[T any](c *cache, key string, probe func() T) T {
+	if c == nil {
+		return probe()
+	}
+
+	var value T
+	if !c.refresh && c.load(key, &value) {
+		return value
+	}
+
+	value = probe()
+	c.store(key, value)
+
+	return value
+}
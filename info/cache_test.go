@@ -0,0 +1,42 @@
+package info
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if _, err := getCacheDir(); err == nil {
+		t.Errorf("cache directory is found without home")
+	}
+
+	if c := newCache(map[string]string{}, "boot"); c != nil {
+		t.Errorf("cache is enabled without home, in %v", c.dir)
+	}
+
+	for _, dir := range DefaultLogoDirs() {
+		if dir.Path != "/usr/share/barkfetch/logos" && dir.Path != "embedded" {
+			t.Errorf("logo directory %v is used without home", dir.Path)
+		}
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newCache(map[string]string{}, "boot")
+	if c == nil || c.dir != filepath.Join(dir, "barkfetch") {
+		t.Errorf("cache is %+v, want it in %v", c, dir)
+	}
+}
@@ -0,0 +1,350 @@
+package info
+
+import "strings"
+
+// Info is a collected system info, fields of disabled modules are left empty
+type Info struct {
+	// User and Host are username and hostname
+	User string `json:"user,omitempty"`
+	Host string `json:"host,omitempty"`
+
+	// FQDN is a fully qualified domain name of host, empty unless title
+	// uses it or if it's unknown
+	FQDN string `json:"fqdn,omitempty"`
+
+	// UserName is a full name of user, empty unless userline_name is
+	// enabled or title uses it, or if it's unknown
+	UserName string `json:"user_name,omitempty"`
+
+	// RealUser is a real user running barkfetch under sudo, empty unless
+	// userline_sudo is enabled or title uses it, or if it's effective user
+	RealUser string `json:"real_user,omitempty"`
+
+	// OS is an OS pretty name and architecture, nil if os is disabled
+	OS *OS `json:"os,omitempty"`
+
+	// Kernel is an OS kernel type and version
+	Kernel string `json:"kernel,omitempty"`
+
+	// Uptime is a system uptime, nil if uptime is disabled or unavailable
+	Uptime *Uptime `json:"uptime,omitempty"`
+
+	// Shell is a current shell
+	Shell string `json:"shell,omitempty"`
+
+	// Resolutions are screen resolutions
+	Resolutions []string `json:"resolutions,omitempty"`
+
+	// CPU is a CPU model
+	CPU string `json:"cpu,omitempty"`
+
+	// GPUs are GPU manufacturers and models
+	GPUs []string `json:"gpus,omitempty"`
+
+	// Memory is a memory usage, nil if memory is disabled or unavailable
+	Memory *Memory `json:"memory,omitempty"`
+
+	// Swap is a swap usage, nil if swap is disabled, unavailable or there
+	// is no swap
+	Swap *Memory `json:"swap,omitempty"`
+
+	// Disks are usages of file systems mounted on disk_mounts
+	Disks []Disk `json:"disks,omitempty"`
+
+	// Batteries are batteries charge
+	Batteries []Battery `json:"batteries,omitempty"`
+
+	// LocalIP and RemoteIP are local and outbound IP
+	LocalIP  string `json:"local_ip,omitempty"`
+	RemoteIP string `json:"remote_ip,omitempty"`
+}
+
+// Helper function, returns true if module is enabled in options
+func isEnabled(options map[string]string, module string) bool {
+	value, exists := options[module]
+	return exists && value != "false"
+}
+
+// Returns mount points of "disk" module from comma-separated disk_mounts
+// option, root by default
+func diskMounts(options map[string]string) []string {
+	mounts := []string{}
+
+	for _, mount := range strings.Split(options["disk_mounts"], ",") {
+		if mount = strings.TrimSpace(mount); mount != "" {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	if len(mounts) == 0 {
+		return []string{"/"}
+	}
+
+	return mounts
+}
+
+// Collect runs probes of modules enabled in options on current machine and
+// returns their data
+func Collect(options map[string]string) Info {
+	return NewSystem().Collect(options)
+}
+
+// Collect runs probes of modules enabled in options and returns their data
+func (s *System) Collect(options map[string]string) Info {
+	var info Info
+
+	// Cache of slow probes, nil if disabled
+	c := newCache(options, s.getRawBootId())
+
+	if isEnabled(options, "userline") || isEnabled(options, "userunderline") {
+		info.User = s.getRawUser()
+		info.Host = s.getRawHostname()
+
+		if titleUses(options, "fqdn", "domain") {
+			info.FQDN = cached(c, "fqdn", s.getRawFQDN)
+		}
+
+		if titleUses(options, "ip") && !isEnabled(options, "localip") {
+			info.LocalIP = s.getRawLocalIp()
+		}
+	}
+
+	if isEnabled(options, "userline") && isEnabled(options, "userline_name") ||
+		titleUses(options, "name") {
+		info.UserName = s.getRawUserName()
+	}
+
+	if isEnabled(options, "userline") && isEnabled(options, "userline_sudo") ||
+		titleUses(options, "real_user") {
+		info.RealUser = s.getRawRealUser()
+	}
+
+	if isEnabled(options, "os") {
+		info.OS = &OS{
+			Name: cached(c, "os", s.getRawPrettyName),
+			Arch: s.getRawArchitecture(),
+		}
+	}
+
+	if isEnabled(options, "kernel") {
+		info.Kernel = cached(c, "kernel", s.getRawKernel)
+	}
+
+	if isEnabled(options, "uptime") {
+		if uptime := newUptime(int(s.getRawUptime())); uptime.Total > 0 {
+			info.Uptime = &uptime
+		}
+	}
+
+	if isEnabled(options, "shell") {
+		info.Shell = s.getRawShell()
+	}
+
+	if isEnabled(options, "resolution") {
+		info.Resolutions = cached(c, "resolution", s.getRawScreenResolutions)
+	}
+
+	if isEnabled(options, "cpu") {
+		info.CPU = cached(c, "cpu", s.getRawCpu)
+	}
+
+	if isEnabled(options, "gpu") {
+		info.GPUs = cached(c, "gpu", s.getRawGpus)
+	}
+
+	if isEnabled(options, "memory") {
+		used, total := s.getRawMemory()
+		if used > 0 && total > 0 {
+			memory := newMemory(used, total)
+			info.Memory = &memory
+		}
+	}
+
+	if isEnabled(options, "swap") {
+		used, total := s.getRawSwap()
+		if total > 0 {
+			swap := newMemory(used, total)
+			info.Swap = &swap
+		}
+	}
+
+	if isEnabled(options, "disk") {
+		info.Disks = s.getRawDisks(diskMounts(options))
+	}
+
+	if isEnabled(options, "battery") {
+		info.Batteries = s.getRawBatteries()
+	}
+
+	if isEnabled(options, "localip") {
+		info.LocalIP = s.getRawLocalIp()
+	}
+
+	if isEnabled(options, "remoteip") {
+		info.RemoteIP = cached(c, "remoteip", s.getRawOutboundIp)
+	}
+
+	return info
+}
+
+// Returns formatted value of single-line module, or "n/a" if probe failed
+func getModuleValue(options map[string]string, info Info, module string) (string, error) {
+	var data any
+
+	switch module {
+	case "os":
+		if info.OS == nil {
+			return "n/a", nil
+		}
+
+		data = *info.OS
+
+	case "kernel":
+		data = info.Kernel
+
+	case "uptime":
+		if info.Uptime == nil {
+			return "n/a", nil
+		}
+
+		data = *info.Uptime
+
+	case "shell":
+		data = info.Shell
+
+	case "cpu":
+		data = info.CPU
+
+	case "memory":
+		if info.Memory == nil {
+			return "n/a", nil
+		}
+
+		data = *info.Memory
+
+	case "swap":
+		if info.Swap == nil {
+			return "n/a", nil
+		}
+
+		data = *info.Swap
+
+	case "localip":
+		data = info.LocalIP
+
+	case "remoteip":
+		data = info.RemoteIP
+	}
+
+	return formatValue(options, module, data)
+}
+
+// Returns formatted values of multi-line module, or single "n/a" if
+// nothing was found
+func getModuleValues(options map[string]string, info Info, module string) ([]string, error) {
+	raw := []any{}
+
+	switch module {
+	case "resolution":
+		for _, resolution := range info.Resolutions {
+			raw = append(raw, resolution)
+		}
+
+	case "gpu":
+		for _, gpu := range info.GPUs {
+			raw = append(raw, gpu)
+		}
+
+	case "disk":
+		for _, disk := range info.Disks {
+			raw = append(raw, disk)
+		}
+
+	case "battery":
+		for _, battery := range info.Batteries {
+			raw = append(raw, battery)
+		}
+	}
+
+	if len(raw) == 0 {
+		return []string{"n/a"}, nil
+	}
+
+	values := []string{}
+
+	for _, data := range raw {
+		value, err := formatValue(options, module, data)
+		if err != nil {
+			return []string{}, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// Returns formatted values of single-line or multi-line module, one for
+// every info line
+func getModuleLineValues(options map[string]string, info Info, module string) ([]string, error) {
+	switch module {
+	case "resolution", "gpu", "disk", "battery":
+		return getModuleValues(options, info, module)
+	}
+
+	value, err := getModuleValue(options, info, module)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return []string{value}, nil
+}
+
+// Measure is a numeric value of module info line, compared with alert
+// thresholds and drawn as percentage bar
+type measure struct {
+	// name is a name of value in multi-line module, like disk mount point
+	// or battery name, empty for single-line modules
+	name string
+
+	// level is a percentage, or seconds for uptime
+	level int64
+
+	// percent is true if level is a percentage, so bar can be drawn
+	percent bool
+}
+
+// Returns measures of module values, one for every value of
+// getModuleLineValues, or none if module reports no number or probe failed
+func getModuleMeasures(info Info, module string) []measure {
+	measures := []measure{}
+
+	switch module {
+	case "uptime":
+		if info.Uptime != nil {
+			measures = append(measures, measure{level: int64(info.Uptime.Total)})
+		}
+
+	case "memory":
+		if info.Memory != nil {
+			measures = append(measures, measure{level: int64(info.Memory.Percent), percent: true})
+		}
+
+	case "swap":
+		if info.Swap != nil {
+			measures = append(measures, measure{level: int64(info.Swap.Percent), percent: true})
+		}
+
+	case "disk":
+		for _, disk := range info.Disks {
+			measures = append(measures, measure{disk.Mount, int64(disk.Percent), true})
+		}
+
+	case "battery":
+		for _, battery := range info.Batteries {
+			measures = append(measures, measure{battery.Name, int64(battery.Percent), true})
+		}
+	}
+
+	return measures
+}
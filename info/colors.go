@@ -1,30 +1,244 @@
 package info
 
-// Colors is a map contains ANSI colors as c0-15 and reset
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Color depths supported by terminals
+const (
+	// Depth16 is a 16 colors terminal, e.g. Linux console
+	Depth16 = 4
+
+	// Depth256 is a 256 colors terminal
+	Depth256 = 8
+
+	// DepthTrueColor is a 24-bit colors terminal
+	DepthTrueColor = 24
+)
+
+// Palette is a RGB values of c0-15 colors, as in xterm defaults, used to
+// downsample truecolor to 16 colors
+var palette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// Named colors, mapped to c0-15
+var namedColors = map[string]int{
+	"black":         0,
+	"red":           1,
+	"green":         2,
+	"yellow":        3,
+	"blue":          4,
+	"magenta":       5,
+	"cyan":          6,
+	"white":         7,
+	"brightblack":   8,
+	"brightred":     9,
+	"brightgreen":   10,
+	"brightyellow":  11,
+	"brightblue":    12,
+	"brightmagenta": 13,
+	"brightcyan":    14,
+	"brightwhite":   15,
+}
+
+// Colors of every color depth, built once and never modified
+var colorsByDepth = map[int]map[string]string{
+	Depth16:        newColors(Depth16),
+	Depth256:       newColors(Depth256),
+	DepthTrueColor: newColors(DepthTrueColor),
+}
+
+// Colors is a map contains ANSI colors as c0-15, named colors, text
+// attributes and reset for 256 colors terminal. Hex colors like #ff8800 and
+// backgrounds like bgc4 are not listed here, but are expanded by
+// ColorExpand. It's a copy, so changing it doesn't change colors Renderer
+// uses, which are of it's own color depth
 // For info, see: https://en.wikipedia.org/wiki/ANSI_escape_code#Colors
-var Colors = map[string]string{
-	"c0":      "\x1b[38;5;0m",
-	"c1":      "\x1b[38;5;1m",
-	"c2":      "\x1b[38;5;2m",
-	"c3":      "\x1b[38;5;3m",
-	"c4":      "\x1b[38;5;4m",
-	"c5":      "\x1b[38;5;5m",
-	"c6":      "\x1b[38;5;6m",
-	"c7":      "\x1b[38;5;7m",
-	"c8":      "\x1b[38;5;8m",
-	"c9":      "\x1b[38;5;9m",
-	"c10":     "\x1b[38;5;10m",
-	"c11":     "\x1b[38;5;11m",
-	"c12":     "\x1b[38;5;12m",
-	"c13":     "\x1b[38;5;13m",
-	"c14":     "\x1b[38;5;14m",
-	"c15":     "\x1b[38;5;15m",
-	"caccent": "", // hack for empty logo
-	"creset":  "\x1b[0m",
-}
-
-// ColorExpand is function returns color value by key argument,
-// to be used with os.Expand()
+var Colors = newColors(Depth256)
+
+// DetectColorDepth returns color depth of terminal by COLORTERM and TERM
+// variables
+func DetectColorDepth() int {
+	colorterm := os.Getenv("COLORTERM")
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return DepthTrueColor
+	}
+
+	term := os.Getenv("TERM")
+	if strings.Contains(term, "direct") || strings.Contains(term, "truecolor") {
+		return DepthTrueColor
+	}
+
+	if strings.Contains(term, "256color") {
+		return Depth256
+	}
+
+	return Depth16
+}
+
+// Returns escape sequence of c0-15 color for color depth
+func paletteColor(index, depth int) string {
+	if depth > Depth16 {
+		return fmt.Sprintf("\x1b[38;5;%vm", index)
+	}
+
+	if index < 8 {
+		return fmt.Sprintf("\x1b[%vm", 30+index)
+	}
+
+	return fmt.Sprintf("\x1b[%vm", 90+index-8)
+}
+
+// Returns colors map for color depth
+func newColors(depth int) map[string]string {
+	colors := map[string]string{
+		"creset":    "\x1b[0m",
+		"reset":     "\x1b[0m",
+		"bold":      "\x1b[1m",
+		"underline": "\x1b[4m",
+	}
+
+	for i := 0; i < 16; i++ {
+		colors[fmt.Sprintf("c%v", i)] = paletteColor(i, depth)
+	}
+
+	for name, index := range namedColors {
+		colors[name] = paletteColor(index, depth)
+	}
+
+	return colors
+}
+
+// Parses #rrggbb hex color to RGB values
+func parseHexColor(color string) (r, g, b int, ok bool) {
+	if len(color) != 7 || color[0] != '#' {
+		return
+	}
+
+	value, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return
+	}
+
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), true
+}
+
+// Returns index of 256 colors palette closest to RGB value, using 6x6x6
+// color cube or grayscale ramp
+func rgbTo256(r, g, b int) int {
+	if r == g && g == b {
+		if r < 8 {
+			return 16
+		}
+
+		if r > 248 {
+			return 231
+		}
+
+		return 232 + (r-8)*24/247
+	}
+
+	cube := func(value int) int {
+		if value < 48 {
+			return 0
+		}
+
+		if value < 115 {
+			return 1
+		}
+
+		return (value - 35) / 40
+	}
+
+	return 16 + 36*cube(r) + 6*cube(g) + cube(b)
+}
+
+// Returns index of c0-15 color closest to RGB value
+func rgbTo16(r, g, b int) int {
+	best, bestDistance := 0, -1
+
+	for i, color := range palette {
+		dr, dg, db := r-color[0], g-color[1], b-color[2]
+		distance := dr*dr + dg*dg + db*db
+
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+
+	return best
+}
+
+// Returns escape sequence of hex color, downsampled to color depth
+func hexColor(color string, depth int) (string, bool) {
+	r, g, b, ok := parseHexColor(color)
+	if !ok {
+		return "", false
+	}
+
+	switch depth {
+	case DepthTrueColor:
+		return fmt.Sprintf("\x1b[38;2;%v;%v;%vm", r, g, b), true
+
+	case Depth256:
+		return fmt.Sprintf("\x1b[38;5;%vm", rgbTo256(r, g, b)), true
+	}
+
+	return paletteColor(rgbTo16(r, g, b), depth), true
+}
+
+// Returns background escape sequence of foreground color escape sequence,
+// false if it's not a color, like bold or reset
+func backgroundColor(foreground string) (string, bool) {
+	parameters := strings.TrimSuffix(strings.TrimPrefix(foreground, "\x1b["), "m")
+
+	if rest, found := strings.CutPrefix(parameters, "38;"); found {
+		return "\x1b[48;" + rest + "m", true
+	}
+
+	code, err := strconv.Atoi(parameters)
+	if err != nil || !(code >= 30 && code <= 37 || code >= 90 && code <= 97) {
+		return "", false
+	}
+
+	return fmt.Sprintf("\x1b[%vm", code+10), true
+}
+
+// Returns color value by name or hex for color depth, prefixed with "bg"
+// for background, like bgc4 or bg#5e81ac. False if color is unknown
+func lookupColor(color string, depth int) (string, bool) {
+	if name, found := strings.CutPrefix(color, "bg"); found {
+		foreground, exists := lookupColor(name, depth)
+		if !exists {
+			return "", false
+		}
+
+		return backgroundColor(foreground)
+	}
+
+	if strings.HasPrefix(color, "#") {
+		return hexColor(color, depth)
+	}
+
+	colors, exists := colorsByDepth[depth]
+	if !exists {
+		colors = colorsByDepth[Depth256]
+	}
+
+	value, exists := colors[color]
+	return value, exists
+}
+
+// ColorExpand is function returns color value by key argument for 256
+// colors terminal, to be used with os.Expand()
 func ColorExpand(color string) string {
-	return Colors[color]
+	value, _ := lookupColor(color, Depth256)
+	return value
 }
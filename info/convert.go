@@ -0,0 +1,344 @@
+package info
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Converting image into logo styles
+var convertStyles = []string{"ascii", "halfblock", "braille"}
+
+// Characters of ascii style, from darkest to brightest
+const asciiRamp = " .:-=+*#%@"
+
+// Pixel of image scaled down for converting, with straight 8-bit color
+type pixel struct {
+	r, g, b int
+	opaque  bool
+}
+
+// Returns luminance of pixel, from 0 to 255
+func (p pixel) luminance() int {
+	return (299*p.r + 587*p.g + 114*p.b) / 1000
+}
+
+// ConvertLogo converts image into logo file contents in "#accent cN"
+// format, width columns wide. Style is "ascii", "halfblock" or "braille",
+// colors are quantized to c0-15 if depth is Depth16, or written as hex
+// colors if depth is DepthTrueColor
+func ConvertLogo(img image.Image, width int, style string, depth int) (string, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", fmt.Errorf("%w: image is empty", ErrInvalidOption)
+	}
+
+	if width <= 0 {
+		return "", fmt.Errorf("%w: width=%v", ErrInvalidOption, width)
+	}
+
+	if depth != Depth16 && depth != DepthTrueColor {
+		return "", fmt.Errorf("%w: depth=%v", ErrInvalidOption, depth)
+	}
+
+	// pixels of every cell, terminal cell is about twice as tall as wide
+	var cellWidth, cellHeight int
+
+	switch style {
+	case "ascii", "halfblock":
+		cellWidth, cellHeight = 1, 2
+
+	case "braille":
+		cellWidth, cellHeight = 2, 4
+
+	default:
+		return "", fmt.Errorf("%w: style=%v, expected one of %v", ErrInvalidOption, style, convertStyles)
+	}
+
+	rows := (bounds.Dy()*width*cellWidth/bounds.Dx() + cellHeight - 1) / cellHeight
+	if rows < 1 {
+		rows = 1
+	}
+
+	pixels := scaleImage(img, width*cellWidth, rows*cellHeight)
+	converter := logoConverter{depth: depth, counts: map[int]int{}, opaque: true}
+
+	for _, row := range pixels {
+		for _, p := range row {
+			converter.opaque = converter.opaque && p.opaque
+		}
+	}
+
+	lines := []string{}
+	for row := 0; row < rows; row++ {
+		cells := pixels[row*cellHeight : (row+1)*cellHeight]
+
+		switch style {
+		case "ascii":
+			lines = append(lines, converter.asciiLine(cells[0]))
+
+		case "halfblock":
+			lines = append(lines, converter.halfblockLine(cells[0], cells[1]))
+
+		case "braille":
+			lines = append(lines, converter.brailleLine(cells))
+		}
+	}
+
+	// drop transparent rows around image
+	for len(lines) > 1 && lines[0] == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return fmt.Sprintf("#accent c%v\n%v", converter.accent(), strings.Join(lines, "\n")), nil
+}
+
+// Scales image to width and height, averaging every pixel over it's area
+func scaleImage(img image.Image, width, height int) [][]pixel {
+	bounds := img.Bounds()
+	pixels := make([][]pixel, height)
+
+	for y := 0; y < height; y++ {
+		pixels[y] = make([]pixel, width)
+
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			// colors are alpha-premultiplied 16-bit
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			if a/count < 0x8000 {
+				continue
+			}
+
+			pixels[y][x] = pixel{
+				r:      int(r * 0xff / a),
+				g:      int(g * 0xff / a),
+				b:      int(b * 0xff / a),
+				opaque: true,
+			}
+		}
+	}
+
+	return pixels
+}
+
+// logoConverter writes logo lines with color directives, counting colors
+// to choose accent
+type logoConverter struct {
+	depth int
+
+	// counts are cells count of every c0-15 color
+	counts map[int]int
+
+	// opaque is true if image has no transparent pixels, so shape is drawn
+	// by brightness instead
+	opaque bool
+}
+
+// Returns color directive name of pixel color, like "c4" or "#5e81ac"
+func (c *logoConverter) color(p pixel) string {
+	c.counts[rgbTo16(p.r, p.g, p.b)]++
+
+	if c.depth == DepthTrueColor {
+		return fmt.Sprintf("#%02x%02x%02x", p.r, p.g, p.b)
+	}
+
+	return fmt.Sprintf("c%v", rgbTo16(p.r, p.g, p.b))
+}
+
+// Returns most used c0-15 color, ignoring black and white if there are
+// others, as they are rarely a good accent
+func (c *logoConverter) accent() int {
+	best := -1
+
+	for _, skipGray := range []bool{true, false} {
+		for color := 0; color < 16; color++ {
+			gray := color == 0 || color == 7 || color == 8 || color == 15
+			if skipGray && gray || c.counts[color] == 0 {
+				continue
+			}
+
+			if best < 0 || c.counts[color] > c.counts[best] {
+				best = color
+			}
+		}
+
+		if best >= 0 {
+			return best
+		}
+	}
+
+	return 7
+}
+
+// lineWriter writes logo line, emitting color directives only on color
+// changes
+type lineWriter struct {
+	builder    strings.Builder
+	foreground string
+	background string
+
+	// spaces are transparent cells not written yet, trailing ones are
+	// dropped
+	spaces int
+}
+
+// Writes cell text with foreground and background color directive names,
+// empty background is terminal background
+func (w *lineWriter) write(text, foreground, background string) {
+	if w.background != "" && background == "" {
+		w.builder.WriteString("${creset}")
+		w.foreground, w.background = "", ""
+	}
+
+	w.builder.WriteString(strings.Repeat(" ", w.spaces))
+	w.spaces = 0
+
+	if foreground != w.foreground {
+		w.builder.WriteString("${" + foreground + "}")
+		w.foreground = foreground
+	}
+
+	if background != w.background {
+		w.builder.WriteString("${bg" + background + "}")
+		w.background = background
+	}
+
+	w.builder.WriteString(text)
+}
+
+// Writes transparent cell
+func (w *lineWriter) space() {
+	if w.background != "" {
+		w.builder.WriteString("${creset}")
+		w.foreground, w.background = "", ""
+	}
+
+	w.spaces++
+}
+
+// Returns written line, ending with reset if color is set, so color
+// doesn't continue into info next to logo
+func (w *lineWriter) String() string {
+	if w.foreground != "" || w.background != "" {
+		return w.builder.String() + "${creset}"
+	}
+
+	return w.builder.String()
+}
+
+// Returns line of characters by brightness of pixels
+func (c *logoConverter) asciiLine(pixels []pixel) string {
+	var w lineWriter
+
+	for _, p := range pixels {
+		if !p.opaque {
+			w.space()
+			continue
+		}
+
+		char := asciiRamp[p.luminance()*(len(asciiRamp)-1)/255]
+		if char == ' ' {
+			// dark pixel is still part of image
+			char = '.'
+		}
+
+		w.write(string(char), c.color(p), "")
+	}
+
+	return w.String()
+}
+
+// Returns line of half blocks, top pixels are drawn with foreground and
+// bottom ones with background
+func (c *logoConverter) halfblockLine(top, bottom []pixel) string {
+	var w lineWriter
+
+	for x := range top {
+		switch {
+		case !top[x].opaque && !bottom[x].opaque:
+			w.space()
+
+		case !bottom[x].opaque:
+			w.write("▀", c.color(top[x]), "")
+
+		case !top[x].opaque:
+			w.write("▄", c.color(bottom[x]), "")
+
+		default:
+			topColor, bottomColor := c.color(top[x]), c.color(bottom[x])
+
+			if topColor == bottomColor {
+				w.write("█", topColor, "")
+			} else {
+				w.write("▀", topColor, bottomColor)
+			}
+		}
+	}
+
+	return w.String()
+}
+
+// Bits of braille dots by their position in cell, 2 columns and 4 rows
+// For info, see: https://en.wikipedia.org/wiki/Braille_Patterns
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Returns line of braille characters, dot is set for every opaque pixel,
+// or for every bright pixel if image is opaque. Cell is colored with
+// average color of it's dots
+func (c *logoConverter) brailleLine(rows [][]pixel) string {
+	var w lineWriter
+
+	for x := 0; x < len(rows[0]); x += 2 {
+		var dots rune
+		var r, g, b, count int
+
+		for dy := range brailleDots {
+			for dx := range brailleDots[dy] {
+				p := rows[dy][x+dx]
+				if !p.opaque || c.opaque && p.luminance() < 128 {
+					continue
+				}
+
+				dots |= brailleDots[dy][dx]
+				r, g, b, count = r+p.r, g+p.g, b+p.b, count+1
+			}
+		}
+
+		if count == 0 {
+			w.space()
+			continue
+		}
+
+		w.write(string(0x2800+dots), c.color(pixel{r / count, g / count, b / count, true}), "")
+	}
+
+	return w.String()
+}
@@ -0,0 +1,91 @@
+package info
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Returns 4x4 image with red left half, and blue right column with
+// transparent top half of column to the left of it
+func convertTestImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	for y := 0; y < 4; y++ {
+		img.Set(0, y, red)
+		img.Set(1, y, red)
+		img.Set(3, y, blue)
+
+		if y >= 2 {
+			img.Set(2, y, blue)
+		}
+	}
+
+	return img
+}
+
+func TestConvertLogo(t *testing.T) {
+	tests := []struct {
+		style string
+		width int
+		depth int
+		want  string
+	}{
+		{"ascii", 4, Depth16, "#accent c9\n${c9}:: ${c4}.${creset}\n${c9}::${c4}..${creset}"},
+		{"ascii", 4, DepthTrueColor, "#accent c9\n${#ff0000}:: ${#0000ff}.${creset}\n${#ff0000}::${#0000ff}..${creset}"},
+		{"halfblock", 4, Depth16, "#accent c9\n${c9}██ ${c4}█${creset}\n${c9}██${c4}██${creset}"},
+		{"braille", 2, Depth16, "#accent c4\n${c9}⣿${c4}⣼${creset}"},
+		{"braille", 2, DepthTrueColor, "#accent c4\n${#ff0000}⣿${#0000ff}⣼${creset}"},
+	}
+
+	for _, test := range tests {
+		logo, err := ConvertLogo(convertTestImage(), test.width, test.style, test.depth)
+		if err != nil {
+			t.Errorf("%v, depth %v: %v", test.style, test.depth, err)
+			continue
+		}
+
+		if logo != test.want {
+			t.Errorf("%v, depth %v: got %q, want %q", test.style, test.depth, logo, test.want)
+		}
+
+		if problems := LintLogo(logo); len(problems) > 0 {
+			t.Errorf("%v, depth %v: lint problems %v", test.style, test.depth, problems)
+		}
+	}
+}
+
+func TestConvertLogoHalfblockBackground(t *testing.T) {
+	// red over blue is one cell, drawn with foreground and background
+	img := image.NewRGBA(image.Rect(0, 0, 1, 2))
+	img.Set(0, 0, color.RGBA{0xff, 0, 0, 0xff})
+	img.Set(0, 1, color.RGBA{0, 0, 0xff, 0xff})
+
+	logo, err := ConvertLogo(img, 1, "halfblock", Depth16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "#accent c4\n${c9}${bgc4}▀${creset}"; logo != want {
+		t.Errorf("got %q, want %q", logo, want)
+	}
+}
+
+func TestConvertLogoInvalid(t *testing.T) {
+	img := convertTestImage()
+
+	for _, test := range []struct {
+		style        string
+		width, depth int
+	}{
+		{"sixel", 4, Depth16},
+		{"ascii", 0, Depth16},
+		{"ascii", 4, Depth256},
+	} {
+		if _, err := ConvertLogo(img, test.width, test.style, test.depth); err == nil {
+			t.Errorf("%+v: no error", test)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package info
+
+import "fmt"
+
+// Aliases of distro IDs to logo names, used when distro has no logo of
+// it's own. IDs are /etc/os-release ID and ID_LIKE values
+var distroAliases = map[string]string{
+	// Red Hat family
+	"ol":     "fedora",
+	"nobara": "fedora",
+
+	// Debian family
+	"devuan": "debian",
+
+	// Ubuntu family
+	"kubuntu": "ubuntu",
+	"xubuntu": "ubuntu",
+	"lubuntu": "ubuntu",
+	"neon":    "ubuntu",
+	"pop":     "popos",
+
+	// Arch family
+	"manjaro-arm": "manjaro",
+
+	// Others
+	"mx":            "mxlinux",
+	"opensuse-leap": "opensuse",
+	"suse":          "opensuse",
+	"sles":          "opensuse",
+}
+
+// Chooses logo for distros, most specific first, trying every distro and
+// it's alias in order. Returns logo name and reason it was chosen
+func chooseLogo(logos []LogoDir, distros []string) (name, reason string) {
+	if len(distros) == 0 {
+		return "default", "distro is unknown"
+	}
+
+	for i, distro := range distros {
+		source := fmt.Sprintf("ID=%v", distro)
+		if i > 0 {
+			source = fmt.Sprintf("ID_LIKE=%v", distro)
+		}
+
+		if hasLogo(logos, distro) {
+			return distro, source
+		}
+
+		alias, exists := distroAliases[distro]
+		if exists && hasLogo(logos, alias) {
+			return alias, fmt.Sprintf("%v, alias of %v", source, alias)
+		}
+	}
+
+	return "default", fmt.Sprintf("no logo for %v", distros)
+}
@@ -0,0 +1,189 @@
+package info
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Default labels of info lines, can be overridden with "label.<module>"
+var defaultLabels = map[string]string{
+	"os":         "OS",
+	"kernel":     "Kernel",
+	"uptime":     "Uptime",
+	"shell":      "Shell",
+	"resolution": "Resolution",
+	"cpu":        "CPU",
+	"gpu":        "GPU",
+	"memory":     "Memory",
+	"swap":       "Swap",
+	"disk":       "Disk",
+	"battery":    "Battery",
+	"localip":    "Local IP",
+	"remoteip":   "Remote IP",
+}
+
+// Default value templates of info lines, can be overridden with
+// "format.<module>", modules missing here are formatted with "{{.}}"
+var defaultFormats = map[string]string{
+	"os":      "{{.Name}} {{.Arch}}",
+	"memory":  "{{.Used | mib}} / {{.Total | mib}} MiB ({{.Percent}}%)",
+	"swap":    "{{.Used | mib}} / {{.Total | mib}} MiB ({{.Percent}}%)",
+	"disk":    "{{.Used | gib}} / {{.Total | gib}} GiB ({{.Percent}}%) on {{.Mount}}",
+	"battery": "{{.Percent}}%{{if .Status}} ({{.Status}}){{end}}",
+}
+
+// Helper functions available in value templates
+var templateFuncs = template.FuncMap{
+	"kib": func(bytes uint64) uint64 { return bytes / (1 << 10) },
+	"mib": func(bytes uint64) uint64 { return bytes / (1 << 20) },
+	"gib": func(bytes uint64) uint64 { return bytes / (1 << 30) },
+}
+
+// OS is a data of "os" module
+type OS struct {
+	// Name is an OS pretty name
+	Name string `json:"name,omitempty"`
+
+	// Arch is an OS architecture
+	Arch string `json:"arch,omitempty"`
+}
+
+// Uptime is a data of "uptime" module
+type Uptime struct {
+	// Total is an uptime in seconds
+	Total int `json:"total"`
+
+	// Days, Hours, Minutes and Seconds are uptime components
+	Days    int `json:"days"`
+	Hours   int `json:"hours"`
+	Minutes int `json:"minutes"`
+	Seconds int `json:"seconds"`
+}
+
+// Creates Uptime from seconds
+func newUptime(seconds int) Uptime {
+	return Uptime{
+		Total:   seconds,
+		Days:    seconds / 86400,
+		Hours:   seconds % 86400 / 3600,
+		Minutes: seconds % 3600 / 60,
+		Seconds: seconds % 60,
+	}
+}
+
+// String returns uptime in "1 d, 2 h, 3 m, 4 s" form, omitting leading zeros
+func (u Uptime) String() string {
+	switch {
+	case u.Total <= 60:
+		return fmt.Sprintf("%v s", u.Total)
+
+	case u.Total <= 3600:
+		return fmt.Sprintf("%v m, %v s", u.Minutes, u.Seconds)
+
+	case u.Total <= 86400:
+		return fmt.Sprintf("%v h, %v m, %v s", u.Total/3600, u.Minutes, u.Seconds)
+	}
+
+	return fmt.Sprintf("%v d, %v h, %v m, %v s", u.Days, u.Hours, u.Minutes, u.Seconds)
+}
+
+// Memory is a data of "memory" module
+type Memory struct {
+	// Used is an used memory in bytes
+	Used uint64 `json:"used"`
+
+	// Total is a total memory in bytes
+	Total uint64 `json:"total"`
+
+	// Percent is a percentage of used memory
+	Percent int `json:"percent"`
+}
+
+// Creates Memory from used and total bytes
+func newMemory(used, total uint64) Memory {
+	return Memory{
+		Used:    used,
+		Total:   total,
+		Percent: int(float64(used) / float64(total) * 100.0),
+	}
+}
+
+// Disk is a data of "disk" module, one for every mount point
+type Disk struct {
+	// Mount is a mount point of file system
+	Mount string `json:"mount"`
+
+	// Used is an used space in bytes
+	Used uint64 `json:"used"`
+
+	// Total is an used and available space in bytes, space reserved for
+	// root is not counted like in "df"
+	Total uint64 `json:"total"`
+
+	// Percent is a percentage of used space
+	Percent int `json:"percent"`
+}
+
+// Creates Disk from mount point, used and total bytes
+func newDisk(mount string, used, total uint64) Disk {
+	return Disk{
+		Mount:   mount,
+		Used:    used,
+		Total:   total,
+		Percent: int(float64(used) / float64(total) * 100.0),
+	}
+}
+
+// Battery is a data of "battery" module, one for every battery
+type Battery struct {
+	// Name is a battery name, like "BAT0"
+	Name string `json:"name"`
+
+	// Percent is a battery charge
+	Percent int `json:"percent"`
+
+	// Status is a charging status, like "charging", empty if it's unknown
+	Status string `json:"status,omitempty"`
+}
+
+// Returns label of module, or it's override from options
+func getLabel(options map[string]string, module string) string {
+	label, exists := options["label."+module]
+	if exists {
+		return label
+	}
+
+	return defaultLabels[module]
+}
+
+// Formats module data with module value template from options
+func formatValue(options map[string]string, module string, data any) (string, error) {
+	format, exists := options["format."+module]
+	if !exists {
+		format, exists = defaultFormats[module]
+	}
+
+	if !exists {
+		format = "{{.}}"
+	}
+
+	tmpl, err := template.New(module).Funcs(templateFuncs).Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("format.%v: %w", module, err)
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("format.%v: %w", module, err)
+	}
+
+	return builder.String(), nil
+}
+
+// Returns labeled info line, painted with theme
+func formatLine(theme Theme, label, value string) string {
+	return theme.paint(theme.Label, label) +
+		theme.paint(theme.Separator, theme.SeparatorText) +
+		theme.paint(theme.Value, value)
+}
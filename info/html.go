@@ -0,0 +1,298 @@
+package info
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HTML page output is wrapped in, colored like terminal palette
+const htmlPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>barkfetch</title>
+</head>
+<body style="margin:0;background-color:%v;color:%v">
+<pre style="margin:1em;font-family:monospace">%v</pre>
+</body>
+</html>
+`
+
+// Levels of 6x6x6 color cube of 256 colors palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// terminalPalette is a colors of terminal HTML and SVG output is drawn
+// like, as CSS colors
+type terminalPalette struct {
+	background, foreground string
+
+	// colors are c0-15 colors
+	colors [16]string
+}
+
+// Reads terminal palette from "palette.background", "palette.foreground"
+// and "palette.c0"-"palette.c15" hex colors, defaults are xterm colors on
+// black background
+func parsePalette(options map[string]string) (terminalPalette, error) {
+	p := terminalPalette{background: "#000000", foreground: "#e5e5e5"}
+
+	for i, color := range palette {
+		p.colors[i] = fmt.Sprintf("#%02x%02x%02x", color[0], color[1], color[2])
+	}
+
+	targets := map[string]*string{
+		"background": &p.background,
+		"foreground": &p.foreground,
+	}
+
+	for i := range p.colors {
+		targets[fmt.Sprintf("c%v", i)] = &p.colors[i]
+	}
+
+	for key, target := range targets {
+		value, exists := options["palette."+key]
+		if !exists || value == "" {
+			continue
+		}
+
+		if _, _, _, ok := parseHexColor(value); !ok {
+			return p, fmt.Errorf("%w: palette.%v=%v", ErrInvalidOption, key, value)
+		}
+
+		*target = strings.ToLower(value)
+	}
+
+	return p, nil
+}
+
+// Returns CSS color of 256 colors palette index
+func (p terminalPalette) color(index int) string {
+	var r, g, b int
+
+	switch {
+	case index < 16:
+		return p.colors[index]
+
+	case index < 232:
+		index -= 16
+		r, g, b = cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]
+
+	default:
+		r = 8 + (index-232)*10
+		g, b = r, r
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// textStyle is a text style set by SGR escape sequences, colors are CSS
+// colors, empty for default ones
+type textStyle struct {
+	foreground, background string
+	bold, underline        bool
+}
+
+// Returns CSS declarations of style, empty for default style
+func (s textStyle) css() string {
+	declarations := []string{}
+
+	if s.foreground != "" {
+		declarations = append(declarations, "color:"+s.foreground)
+	}
+
+	if s.background != "" {
+		declarations = append(declarations, "background-color:"+s.background)
+	}
+
+	if s.bold {
+		declarations = append(declarations, "font-weight:bold")
+	}
+
+	if s.underline {
+		declarations = append(declarations, "text-decoration:underline")
+	}
+
+	return strings.Join(declarations, ";")
+}
+
+// Applies SGR parameters, like "1;38;5;4", to style with palette colors.
+// Unsupported ones are ignored
+func (s *textStyle) apply(parameters string, p terminalPalette) {
+	codes := strings.Split(parameters, ";")
+
+	for i := 0; i < len(codes); i++ {
+		// empty parameter is reset
+		code, _ := strconv.Atoi(codes[i])
+
+		switch {
+		case code == 0:
+			*s = textStyle{}
+
+		case code == 1:
+			s.bold = true
+
+		case code == 4:
+			s.underline = true
+
+		case code == 22:
+			s.bold = false
+
+		case code == 24:
+			s.underline = false
+
+		case code == 39:
+			s.foreground = ""
+
+		case code == 49:
+			s.background = ""
+
+		case code >= 30 && code <= 37:
+			s.foreground = p.color(code - 30)
+
+		case code >= 90 && code <= 97:
+			s.foreground = p.color(code - 90 + 8)
+
+		case code >= 40 && code <= 47:
+			s.background = p.color(code - 40)
+
+		case code >= 100 && code <= 107:
+			s.background = p.color(code - 100 + 8)
+
+		case code == 38, code == 48:
+			color, count := extendedColor(codes[i+1:], p)
+			i += count
+
+			if code == 38 {
+				s.foreground = color
+			} else {
+				s.background = color
+			}
+		}
+	}
+}
+
+// Returns CSS color of extended color parameters following 38 or 48, like
+// "5;n" or "2;r;g;b", and count of parameters it takes
+func extendedColor(codes []string, p terminalPalette) (string, int) {
+	numbers := []int{}
+	for _, code := range codes {
+		number, _ := strconv.Atoi(code)
+		numbers = append(numbers, number&0xff)
+	}
+
+	switch {
+	case len(numbers) >= 2 && numbers[0] == 5:
+		return p.color(numbers[1]), 2
+
+	case len(numbers) >= 4 && numbers[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", numbers[1], numbers[2], numbers[3]), 4
+	}
+
+	return "", len(codes)
+}
+
+// styledRun is a text drawn with one style
+type styledRun struct {
+	text  string
+	style textStyle
+}
+
+// Splits lines with ANSI colors into runs of styled text, style is carried
+// over to next lines like in terminal. Escape sequences other than colors
+// are dropped
+func styledLines(lines []string, p terminalPalette) [][]styledRun {
+	var style textStyle
+	styled := [][]styledRun{}
+
+	for _, line := range lines {
+		runs := []styledRun{}
+
+		add := func(text string) {
+			if text != "" {
+				runs = append(runs, styledRun{text: text, style: style})
+			}
+		}
+
+		last := 0
+		for _, match := range escapeSequenceRegex.FindAllStringIndex(line, -1) {
+			add(line[last:match[0]])
+			last = match[1]
+
+			if sequence := line[match[0]:match[1]]; strings.HasSuffix(sequence, "m") {
+				style.apply(sequence[2:len(sequence)-1], p)
+			}
+		}
+
+		add(line[last:])
+		styled = append(styled, runs)
+	}
+
+	return styled
+}
+
+// ConvertHTML converts lines with ANSI colors, like lines of RenderLines,
+// into standalone HTML page. Colors are drawn with palette from
+// "palette.<color>" options
+func ConvertHTML(lines []string, options map[string]string) (string, error) {
+	p, err := parsePalette(options)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+
+	for i, runs := range styledLines(lines, p) {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+
+		for _, run := range runs {
+			if css := run.style.css(); css != "" {
+				fmt.Fprintf(&output, `<span style="%v">%v</span>`, css, html.EscapeString(run.text))
+			} else {
+				output.WriteString(html.EscapeString(run.text))
+			}
+		}
+	}
+
+	return fmt.Sprintf(htmlPage, p.background, p.foreground, output.String()), nil
+}
+
+// Returns lines of processed info in truecolor, rendered with template
+// file from "template" option if it's set. Renderer isn't changed
+func (r *Renderer) truecolorLines(options map[string]string) ([]string, error) {
+	renderer := *r
+	renderer.ColorDepth = DepthTrueColor
+
+	path := options["template"]
+	if path == "" {
+		return renderer.RenderLines(options)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return []string{}, err
+	}
+
+	output, err := renderer.RenderTemplate(options, string(raw))
+	if err != nil {
+		return []string{}, err
+	}
+
+	return strings.Split(strings.TrimSuffix(output, "\n"), "\n"), nil
+}
+
+// RenderHTML returns processed info as standalone HTML page, with colors
+// rendered in truecolor, or template file from "template" option rendered
+// same way. Image logos are not drawn
+func (r *Renderer) RenderHTML(options map[string]string) (string, error) {
+	lines, err := r.truecolorLines(options)
+	if err != nil {
+		return "", err
+	}
+
+	return ConvertHTML(lines, options)
+}
@@ -0,0 +1,330 @@
+package info
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	// Decoders of supported image formats
+	_ "image/jpeg"
+)
+
+// Image backends, escape protocols image logos are drawn with
+var imageBackends = []string{"kitty", "iterm2", "sixel"}
+
+// Cell size used if terminal doesn't report it's pixel size
+const (
+	defaultCellWidth  = 8
+	defaultCellHeight = 16
+)
+
+// Default maximal width of image logo, in columns
+const defaultImageWidth = 30
+
+// DetectImageBackend returns image backend terminal supports by
+// environment variables, or empty string if it supports none
+func DetectImageBackend() string {
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty",
+		program == "ghostty":
+		return "kitty"
+
+	case program == "iTerm.app", program == "WezTerm",
+		os.Getenv("LC_TERMINAL") == "iTerm2":
+		return "iterm2"
+
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.Contains(term, "sixel"):
+		return "sixel"
+	}
+
+	return ""
+}
+
+// Returns image logo from logo_image option, drawn with image_backend
+// option or detected backend. Returns empty Logo if terminal can't draw
+// images or image doesn't fit, so text logo is used instead
+func (r *Renderer) imageLogo(options map[string]string, l layout, infoWidth int) (Logo, error) {
+	backend := options["image_backend"]
+	if backend == "" || backend == "auto" {
+		backend = r.ImageBackend
+	}
+
+	if backend == "" {
+		r.debugf("logo: terminal supports no image backend, chose text logo")
+		return Logo{}, nil
+	}
+
+	known := false
+	for _, imageBackend := range imageBackends {
+		known = known || imageBackend == backend
+	}
+
+	if !known {
+		return Logo{}, fmt.Errorf("%w: image_backend=%v", ErrInvalidOption, backend)
+	}
+
+	if l.position != "left" {
+		r.debugf("logo: image logo is only drawn on the left, chose text logo")
+		return Logo{}, nil
+	}
+
+	if options["logo_size"] == "none" {
+		return Logo{}, nil
+	}
+
+	data, err := os.ReadFile(options["logo_image"])
+	if err != nil {
+		return Logo{}, fmt.Errorf("logo_image: %w", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Logo{}, fmt.Errorf("logo_image %v: %w", options["logo_image"], err)
+	}
+
+	cellWidth, cellHeight := r.CellWidth, r.CellHeight
+	if cellWidth <= 0 || cellHeight <= 0 {
+		cellWidth, cellHeight = defaultCellWidth, defaultCellHeight
+	}
+
+	columns, rows, err := imageCells(img, options["logo_image_width"], cellWidth, cellHeight)
+	if err != nil {
+		return Logo{}, err
+	}
+
+	if !l.fits(Logo{MaxLength: columns}, infoWidth, r.TerminalWidth) {
+		r.debugf("logo: image doesn't fit, terminal is %v columns wide", r.TerminalWidth)
+		return Logo{}, nil
+	}
+
+	r.debugf("logo: drew %v with %v, %vx%v cells", options["logo_image"], backend, columns, rows)
+
+	var escape string
+
+	switch backend {
+	case "kitty":
+		escape, err = encodeKitty(img, columns, rows)
+
+	case "iterm2":
+		escape, err = encodeITerm2(img, columns, rows)
+
+	case "sixel":
+		escape = encodeSixel(img, columns*cellWidth, rows*cellHeight)
+	}
+
+	if err != nil {
+		return Logo{}, fmt.Errorf("logo_image: %w", err)
+	}
+
+	return Logo{
+		Logo:      strings.Repeat("\n", rows-1),
+		Lines:     rows,
+		MaxLength: columns,
+		image:     escape,
+	}, nil
+}
+
+// Returns size of image in terminal cells, width is logo_image_width
+// option, or image width capped to default width if it is empty or zero
+func imageCells(img image.Image, width string, cellWidth, cellHeight int) (columns, rows int, err error) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return 0, 0, fmt.Errorf("%w: logo_image is empty", ErrInvalidOption)
+	}
+
+	if width == "" || width == "0" {
+		columns = (bounds.Dx() + cellWidth - 1) / cellWidth
+		if columns > defaultImageWidth {
+			columns = defaultImageWidth
+		}
+	} else {
+		columns, err = strconv.Atoi(width)
+		if err != nil || columns <= 0 {
+			return 0, 0, fmt.Errorf("%w: logo_image_width=%v", ErrInvalidOption, width)
+		}
+	}
+
+	// height in pixels of image scaled to columns, rounded up to cells
+	height := bounds.Dy() * columns * cellWidth / bounds.Dx()
+	rows = (height + cellHeight - 1) / cellHeight
+	if rows < 1 {
+		rows = 1
+	}
+
+	return columns, rows, nil
+}
+
+// Returns image encoded as PNG in base64
+func encodeBase64PNG(img image.Image) (string, error) {
+	var buffer bytes.Buffer
+
+	if err := png.Encode(&buffer, img); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// Returns kitty graphics protocol escape sequence drawing image scaled to
+// cells, sent in chunks of 4096 bytes as protocol requires
+// For info, see: https://sw.kovidgoyal.net/kitty/graphics-protocol/
+func encodeKitty(img image.Image, columns, rows int) (string, error) {
+	encoded, err := encodeBase64PNG(img)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+
+	for start := 0; start < len(encoded); start += 4096 {
+		end, more := start+4096, 1
+		if end >= len(encoded) {
+			end, more = len(encoded), 0
+		}
+
+		if start == 0 {
+			fmt.Fprintf(&output, "\x1b_Ga=T,f=100,q=2,c=%v,r=%v,m=%v;", columns, rows, more)
+		} else {
+			fmt.Fprintf(&output, "\x1b_Gm=%v;", more)
+		}
+
+		output.WriteString(encoded[start:end] + "\x1b\\")
+	}
+
+	return output.String(), nil
+}
+
+// Returns iTerm2 inline image escape sequence drawing image scaled to cells
+// For info, see: https://iterm2.com/documentation-images.html
+func encodeITerm2(img image.Image, columns, rows int) (string, error) {
+	encoded, err := encodeBase64PNG(img)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"\x1b]1337;File=inline=1;width=%v;height=%v;preserveAspectRatio=0:%v\a",
+		columns, rows, encoded,
+	), nil
+}
+
+// Returns sixel escape sequence drawing image scaled to width and height in
+// pixels, with colors quantized to 6x6x6 color cube. Transparent pixels are
+// left undrawn
+// For info, see: https://vt100.net/docs/vt3xx-gp/chapter14.html
+func encodeSixel(img image.Image, width, height int) string {
+	bounds := img.Bounds()
+
+	// color cube index of every pixel, -1 is transparent
+	pixels := make([]int, width*height)
+	used := [216]bool{}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, a := img.At(
+				bounds.Min.X+x*bounds.Dx()/width,
+				bounds.Min.Y+y*bounds.Dy()/height,
+			).RGBA()
+
+			if a < 0x8000 {
+				pixels[y*width+x] = -1
+				continue
+			}
+
+			index := int((r*5+0x7fff)/0xffff)*36 + int((g*5+0x7fff)/0xffff)*6 +
+				int((b*5+0x7fff)/0xffff)
+
+			pixels[y*width+x] = index
+			used[index] = true
+		}
+	}
+
+	var output strings.Builder
+
+	fmt.Fprintf(&output, "\x1bP0;1q\"1;1;%v;%v", width, height)
+
+	for index, isUsed := range used {
+		if isUsed {
+			fmt.Fprintf(&output, "#%v;2;%v;%v;%v", index,
+				index/36*20, index/6%6*20, index%6*20)
+		}
+	}
+
+	row := make([]byte, width)
+
+	for band := 0; band < height; band += 6 {
+		first := true
+
+		for index, isUsed := range used {
+			if !isUsed {
+				continue
+			}
+
+			present := false
+
+			for x := 0; x < width; x++ {
+				bits := 0
+
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+
+				row[x] = byte(63 + bits)
+				present = present || bits != 0
+			}
+
+			if !present {
+				continue
+			}
+
+			if !first {
+				output.WriteByte('$')
+			}
+			first = false
+
+			fmt.Fprintf(&output, "#%v%v", index, sixelRunLength(row))
+		}
+
+		output.WriteByte('-')
+	}
+
+	output.WriteString("\x1b\\")
+
+	return output.String()
+}
+
+// Returns sixel row with repeats compressed as "!N<sixel>", trailing empty
+// sixels are trimmed
+func sixelRunLength(row []byte) string {
+	row = bytes.TrimRight(row, "?")
+
+	var output strings.Builder
+
+	for start := 0; start < len(row); {
+		end := start
+		for end < len(row) && row[end] == row[start] {
+			end++
+		}
+
+		if count := end - start; count > 3 {
+			fmt.Fprintf(&output, "!%v%c", count, row[start])
+		} else {
+			output.Write(row[start:end])
+		}
+
+		start = end
+	}
+
+	return output.String()
+}
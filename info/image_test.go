@@ -0,0 +1,206 @@
+package info
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Returns image of noise, so it's PNG doesn't compress
+func noiseImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	random := rand.New(rand.NewSource(1))
+	random.Read(img.Pix)
+
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	return img
+}
+
+func TestImageCells(t *testing.T) {
+	tests := []struct {
+		width, height int
+		option        string
+		columns, rows int
+	}{
+		{100, 50, "", 13, 4},
+		{8, 8, "0", 1, 1},
+		{1000, 10, "", 30, 1},
+		{16, 16, "4", 4, 2},
+		{16, 17, "4", 4, 3},
+	}
+
+	for _, test := range tests {
+		img := image.NewRGBA(image.Rect(0, 0, test.width, test.height))
+
+		columns, rows, err := imageCells(img, test.option, 8, 16)
+		if err != nil {
+			t.Errorf("%vx%v, width %q: %v", test.width, test.height, test.option, err)
+			continue
+		}
+
+		if columns != test.columns || rows != test.rows {
+			t.Errorf("%vx%v, width %q: got %vx%v cells, want %vx%v",
+				test.width, test.height, test.option, columns, rows, test.columns, test.rows)
+		}
+	}
+
+	for _, option := range []string{"abc", "-1"} {
+		_, _, err := imageCells(image.NewRGBA(image.Rect(0, 0, 8, 8)), option, 8, 16)
+		if !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("width %q: got %v, want ErrInvalidOption", option, err)
+		}
+	}
+
+	_, _, err := imageCells(image.NewRGBA(image.Rect(0, 0, 0, 0)), "", 8, 16)
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("empty image: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestEncodeKittyChunks(t *testing.T) {
+	img := noiseImage(64, 64)
+
+	encoded, err := encodeBase64PNG(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(encoded) <= 2*4096 {
+		t.Fatalf("encoded image is %v bytes, want more than two chunks", len(encoded))
+	}
+
+	escape, err := encodeKitty(img, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := strings.SplitAfter(escape, "\x1b\\")
+	if chunks[len(chunks)-1] != "" {
+		t.Fatalf("escape doesn't end with ST: %q", chunks[len(chunks)-1])
+	}
+	chunks = chunks[:len(chunks)-1]
+
+	if want := (len(encoded) + 4095) / 4096; len(chunks) != want {
+		t.Fatalf("got %v chunks, want %v", len(chunks), want)
+	}
+
+	var payload strings.Builder
+
+	for i, chunk := range chunks {
+		header := "\x1b_Gm=1;"
+		switch {
+		case i == 0:
+			header = "\x1b_Ga=T,f=100,q=2,c=4,r=2,m=1;"
+		case i == len(chunks)-1:
+			header = "\x1b_Gm=0;"
+		}
+
+		if !strings.HasPrefix(chunk, header) {
+			t.Fatalf("chunk %v starts with %q, want %q", i, chunk[:len(header)], header)
+		}
+
+		data := strings.TrimSuffix(strings.TrimPrefix(chunk, header), "\x1b\\")
+		if len(data) > 4096 || (i < len(chunks)-1 && len(data) != 4096) {
+			t.Errorf("chunk %v is %v bytes", i, len(data))
+		}
+
+		payload.WriteString(data)
+	}
+
+	if payload.String() != encoded {
+		t.Errorf("chunks don't add up to encoded image")
+	}
+
+	escape, err = encodeKitty(image.NewRGBA(image.Rect(0, 0, 1, 1)), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(escape, "\x1b_Ga=T,f=100,q=2,c=1,r=1,m=0;") || strings.Count(escape, "\x1b\\") != 1 {
+		t.Errorf("small image isn't sent in one chunk: %q", escape)
+	}
+}
+
+func TestEncodeITerm2(t *testing.T) {
+	img := noiseImage(4, 4)
+
+	escape, err := encodeITerm2(img, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := "\x1b]1337;File=inline=1;width=3;height=2;preserveAspectRatio=0:"
+	if !strings.HasPrefix(escape, header) || !strings.HasSuffix(escape, "\a") {
+		t.Fatalf("escape is %q", escape)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(escape, header), "\a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Bounds() != img.Bounds() || decoded.At(1, 2) != img.At(1, 2) {
+		t.Errorf("decoded image doesn't match encoded one")
+	}
+}
+
+func TestEncodeSixel(t *testing.T) {
+	// two bands: red and blue columns in first, red line with transparent
+	// pixel in second
+	img := image.NewRGBA(image.Rect(0, 0, 8, 7))
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 8; x++ {
+			switch {
+			case y == 6 && x == 7:
+				continue
+			case y < 6 && x >= 5:
+				img.Set(x, y, blue)
+			default:
+				img.Set(x, y, red)
+			}
+		}
+	}
+
+	want := "\x1bP0;1q\"1;1;8;7" +
+		"#5;2;0;0;100#180;2;100;0;0" +
+		"#5!5?~~~$#180!5~-" +
+		"#180!7@-" +
+		"\x1b\\"
+
+	if got := encodeSixel(img, 8, 7); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSixelRunLength(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"????":         "",
+		"~~~":          "~~~",
+		"~~~~":         "!4~",
+		"@@@@@AA??~??": "!5@AA??~",
+	}
+
+	for row, want := range tests {
+		if got := sixelRunLength([]byte(row)); got != want {
+			t.Errorf("%q: got %q, want %q", row, got, want)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package info
 
 import (
-	"fmt"
 	"os"
 	"regexp"
 	"strings"
@@ -9,270 +8,240 @@ import (
 
 // Possible options, to make output sorted independent of config/cmd
 var possibleOptions = []string{"logo", "userline", "userunderline", "os",
-	"kernel", "uptime", "shell", "resolution", "cpu", "gpu", "memory", "localip",
-	"remoteip", "colors"}
+	"kernel", "uptime", "shell", "resolution", "cpu", "gpu", "memory", "swap",
+	"disk", "battery", "localip", "remoteip", "colors"}
+
+// Modules returns names of every module option, in order of output
+func Modules() []string {
+	return append([]string{}, possibleOptions...)
+}
 
 // Regexp matching empty lines, useful to make output more pretty
 var emptyLinesRegex = regexp.MustCompile(`(?m)\n$`)
 
-// Helper function, chains fmt.Sprintf and os.Expand(..., ColorExpand)
-func formatAndColor(format string, args ...any) string {
-	return os.Expand(
-		fmt.Sprintf(format, args...),
-		ColorExpand,
-	)
-}
+// Returns title line, followed by full name and real user if they are
+// enabled
+func userLine(options map[string]string, info Info, theme Theme) (string, error) {
+	title, err := titleLine(options, info, theme)
+	if err != nil {
+		return "", err
+	}
 
-// Returns processed info for pretty output
-func GetInfoString(options map[string]string) string {
-	// out string
-	var output string
+	extras := []string{}
+	if isEnabled(options, "userline_name") && info.UserName != "" {
+		extras = append(extras, info.UserName)
+	}
 
-	// offset for printing labels
-	var offset int
+	if isEnabled(options, "userline_sudo") && info.RealUser != "" &&
+		info.RealUser != info.User {
+		extras = append(extras, "real user "+info.RealUser)
+	}
 
-	// Info lines count, for calibrating newlines at the end
-	var logolines, lines int
+	if len(extras) > 0 {
+		title += " " + theme.paint(theme.Separator, "("+strings.Join(extras, ", ")+")")
+	}
 
-	for _, possibleOption := range possibleOptions {
-		value, exists := options[possibleOption]
-		if !exists {
-			continue
-		}
+	return title, nil
+}
+
+// Returns info lines of modules enabled in options, painted with theme
+func infoLines(options map[string]string, info Info, theme Theme) ([]string, error) {
+	lines := []string{}
+
+	bars, err := parseBarStyle(options)
+	if err != nil {
+		return lines, err
+	}
 
-		if value == "false" {
+	for _, possibleOption := range possibleOptions {
+		if !isEnabled(options, possibleOption) {
 			continue
 		}
 
 		switch possibleOption {
-		case "logo":
-			logo := getLogo(value)
-
-			output += os.Expand(logo.Logo, ColorExpand) +
-				strings.Repeat("\x1b[F", logo.Lines-1)
-			offset = logo.MaxLength + 2
-			Colors["caccent"] = Colors[logo.AccentColor]
-			logolines = logo.Lines
-
 		case "userline":
-			username := getRawUser()
-			hostname := getRawHostname()
-
-			output += formatAndColor(
-				"\x1b[%vG${caccent}%v${creset}@${caccent}%v${creset}\n",
-				offset,
-				username,
-				hostname,
-			)
-			lines++
-
-		case "userunderline":
-			username := getRawUser()
-			hostname := getRawHostname()
-
-			output += formatAndColor(
-				"\x1b[%vG%v\n",
-				offset,
-				strings.Repeat("-", len(username)+len(hostname)),
-			)
-			lines++
-
-		case "os":
-			os := getRawPrettyName()
-			arch := getRawArchitecture()
-
-			output += formatAndColor(
-				"\x1b[%vG${caccent}OS${creset}: %v %v\n",
-				offset,
-				os,
-				arch,
-			)
-			lines++
-
-		case "kernel":
-			kernel := getRawKernel()
-
-			output += formatAndColor(
-				"\x1b[%vG${caccent}Kernel${creset}: %v\n",
-				offset,
-				kernel,
-			)
-			lines++
+			title, err := userLine(options, info, theme)
+			if err != nil {
+				return []string{}, err
+			}
 
-		case "uptime":
-			uptime := getRawUptime()
+			lines = append(lines, title)
 
-			if uptime <= 0 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Uptime:${creset}: n/a\n",
-					offset,
-				)
+		case "userunderline":
+			title, err := userLine(options, info, theme)
+			if err != nil {
+				return []string{}, err
 			}
 
-			if uptime > 0 && uptime <= 60 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Uptime${creset}: %v s\n",
-					offset,
-					int(uptime),
-				)
-			}
+			lines = append(lines, underlineLine(title, theme))
 
-			if uptime > 60 && uptime <= 3600 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Uptime${creset}: %v m, %v s\n",
-					offset,
-					int(uptime/60),
-					int(uptime%60),
-				)
+		case "os", "kernel", "uptime", "shell", "cpu", "memory", "swap",
+			"localip", "remoteip", "resolution", "gpu", "disk", "battery":
+			values, err := getModuleLineValues(options, info, possibleOption)
+			if err != nil {
+				return []string{}, err
 			}
 
-			if uptime > 3600 && uptime <= 86400 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Uptime${creset}: %v h, %v m, %v s\n",
-					offset,
-					int(uptime/3600),
-					int((uptime%3600)/60),
-					int((uptime%3600)%60),
-				)
-			}
+			measures := getModuleMeasures(info, possibleOption)
+
+			for i, value := range values {
+				// value painted with alert color isn't painted again
+				lineTheme := theme
+
+				if i < len(measures) {
+					m := measures[i]
+
+					limits, err := getThresholds(options, possibleOption, m.name)
+					if err != nil {
+						return []string{}, err
+					}
+
+					alert := limits.color(theme, m.level)
+					if alert != "" {
+						value = theme.paint(alert, value)
+						lineTheme.Value = ""
+					}
+
+					if m.percent {
+						barColor := bars.color(theme, int(m.level))
+						if possibleOption == "battery" {
+							barColor = bars.color(theme, 100-int(m.level))
+						}
+
+						if limits.set() {
+							barColor = alert
+							if alert == "" {
+								barColor = theme.Good
+							}
+						}
+
+						value = bars.apply(theme, value, int(m.level), barColor)
+					}
+				}
 
-			if uptime > 86400 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Uptime${creset}: %v d, %v h, %v m, %v s\n",
-					offset,
-					int(uptime/86400),
-					int((uptime%86400)/3600),
-					int(((uptime%86400)%3600)/60),
-					int(((uptime%86400)%3600)%60),
-				)
+				lines = append(lines, formatLine(lineTheme, getLabel(options, possibleOption), value))
 			}
 
-			lines++
-
-		case "shell":
-			shell := getRawShell()
+		case "colors":
+			colors := getRawColors()
 
-			output += formatAndColor(
-				"\x1b[%vG${caccent}Shell${creset}: %v\n",
-				offset,
-				shell,
+			// two rows of 8 colors
+			lines = append(lines,
+				os.Expand(strings.Join(colors[:8], ""), theme.expand),
+				os.Expand(strings.Join(colors[8:], ""), theme.expand),
 			)
-			lines++
-
-		case "resolution":
-			resolutions := getRawScreenResolutions()
-
-			for _, res := range resolutions {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Resolution${creset}: %v\n",
-					offset,
-					res,
-				)
-				lines++
-			}
+		}
+	}
 
-			if len(resolutions) == 0 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Resolution${creset}: n/a\n",
-					offset,
-				)
-				lines++
-			}
+	return lines, nil
+}
 
-		case "cpu":
-			cpu := getRawCpu()
+// Collect returns info of modules enabled in options, redacted if redact
+// is enabled
+func (r *Renderer) Collect(options map[string]string) (Info, error) {
+	return redactInfo(options, r.System.Collect(options))
+}
 
-			output += formatAndColor(
-				"\x1b[%vG${caccent}CPU${creset}: %v\n",
-				offset,
-				cpu,
-			)
-			lines++
+// Render returns processed info for pretty output, or error if value
+// template, theme or logo from options is invalid
+func (r *Renderer) Render(options map[string]string) (string, error) {
+	output, err := r.render(options, true)
+	if err != nil {
+		return "", err
+	}
 
-		case "gpu":
-			gpus := getRawGpus()
+	return output.layout.compose(output.theme, output.logo, output.lines), nil
+}
 
-			if len(gpus) == 0 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}GPU${creset}: n/a\n",
-					offset,
-				)
-			}
+// RenderLines returns processed info as lines padded with spaces instead of
+// cursor movements, so it can be shown outside of terminal, e.g. in browser.
+// Image logos are not drawn
+func (r *Renderer) RenderLines(options map[string]string) ([]string, error) {
+	output, err := r.render(options, false)
+	if err != nil {
+		return []string{}, err
+	}
 
-			for _, gpu := range gpus {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}GPU${creset}: %v\n",
-					offset,
-					gpu,
-				)
-				lines++
-			}
+	return output.layout.lines(output.theme, output.logo, output.lines), nil
+}
 
-		case "memory":
-			used, total := getRawMemory()
-
-			if used <= 0 || total <= 0 {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Memory${creset}: n/a\n",
-					offset,
-				)
-			} else {
-				output += formatAndColor(
-					"\x1b[%vG${caccent}Memory${creset}: %v / %v Mb (%v%%)\n",
-					offset,
-					used,
-					total,
-					int(float32(used)/float32(total)*100.0),
-				)
-			}
+// rendering is a processed info, composed into output by it's layout
+type rendering struct {
+	layout layout
+	theme  Theme
+	logo   Logo
+	lines  []string
+}
+
+// Processes info, theme and logo of options, image logo is chosen only if
+// images is true
+func (r *Renderer) render(options map[string]string, images bool) (rendering, error) {
+	// Collected info of enabled modules, redacted if redact is enabled
+	info, err := r.Collect(options)
+	if err != nil {
+		return rendering{}, err
+	}
 
-			lines++
+	// Colors and decorations of output parts
+	theme, err := loadTheme(options, r.ColorDepth)
+	if err != nil {
+		return rendering{}, err
+	}
 
-		case "localip":
-			localip := getRawLocalIp()
+	// Placement of logo relative to info
+	layout, err := parseLayout(options)
+	if err != nil {
+		return rendering{}, err
+	}
 
-			output += formatAndColor(
-				"\x1b[%vG${caccent}Local IP${creset}: %v\n",
-				offset,
-				localip,
-			)
-			lines++
+	// logo is read before info lines, as it sets accent color
+	var logo Logo
+	var logoName string
+	var logoDir LogoDir
 
-		case "remoteip":
-			remoteip := getRawOutboundIp()
+	if isEnabled(options, "logo") {
+		logoName, logoDir, logo, err = r.getLogo(options["logo"])
+		if err != nil {
+			return rendering{}, err
+		}
 
-			output += formatAndColor(
-				"\x1b[%vG${caccent}Remote IP${creset}: %v\n",
-				offset,
-				remoteip,
-			)
-			lines++
+		if theme.Accent == "" {
+			theme.Accent = logo.AccentColor
+		}
+	}
 
-		case "colors":
-			colors := getRawColors()
-			i := 0
+	lines, err := infoLines(options, info, theme)
+	if err != nil {
+		return rendering{}, err
+	}
 
-			output += fmt.Sprintf("\x1b[%vG", offset)
+	if layout.position == "hidden" {
+		r.debugf("logo: hidden, logo_position is hidden")
+		logo = Logo{}
+	}
 
-			for _, color := range colors {
-				if i == 8 {
-					output += fmt.Sprintf("\n\x1b[%vG", offset)
-					lines++
-					i = 0
-				}
+	if isEnabled(options, "logo") && logo.Lines > 0 {
+		infoWidth := maxWidth(lines)
 
-				output += formatAndColor("%v", color)
-				i++
+		var image Logo
+		if images && options["logo_image"] != "" {
+			image, err = r.imageLogo(options, layout, infoWidth)
+			if err != nil {
+				return rendering{}, err
 			}
-			lines++
 		}
-	}
-
-	output = emptyLinesRegex.ReplaceAllString(output, "")
 
-	if lines < logolines {
-		output += strings.Repeat("\n", logolines-lines)
+		if image.Lines > 0 {
+			logo = image
+		} else {
+			logo, err = r.fitLogo(logoName, logoDir, logo, options["logo_size"], func(logo Logo) bool {
+				return layout.fits(logo, infoWidth, r.TerminalWidth)
+			})
+			if err != nil {
+				return rendering{}, err
+			}
+		}
 	}
 
-	return output
+	return rendering{layout: layout, theme: theme, logo: logo, lines: lines}, nil
 }
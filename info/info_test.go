@@ -0,0 +1,62 @@
+package info
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnderlineCoversUserLine(t *testing.T) {
+	options := map[string]string{
+		"userline":      "true",
+		"userunderline": "true",
+		"userline_name": "true",
+		"userline_sudo": "true",
+	}
+
+	theme, err := loadTheme(options, Depth256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sysinfo := Info{User: "root", Host: "box", UserName: "Jo Doe", RealUser: "jo"}
+
+	lines, err := infoLines(options, sysinfo, theme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("got lines %q", lines)
+	}
+
+	title, underline := StripEscapes(lines[0]), StripEscapes(lines[1])
+	if title != "root@box (Jo Doe, real user jo)" || displayWidth(underline) != displayWidth(title) {
+		t.Errorf("got title %q, underline %q", title, underline)
+	}
+}
+
+func TestRenderHTMLTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fetch.tmpl")
+	if err := os.WriteFile(path, []byte("${c1}{{.User}}${creset} <b>\nsecond\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	renderer := &Renderer{System: fixtureSystem("debian-12-x86_64"), ColorDepth: Depth256}
+	options := map[string]string{"cache": "false", "userline": "true", "template": path}
+
+	output, err := renderer.RenderHTML(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if renderer.ColorDepth != Depth256 {
+		t.Errorf("color depth of renderer changed to %v", renderer.ColorDepth)
+	}
+
+	user := renderer.System.getRawUser()
+	if !strings.Contains(output, `">`+user+`</span> &lt;b&gt;`+"\nsecond</pre>") {
+		t.Errorf("template isn't rendered in page:\n%v", output)
+	}
+}
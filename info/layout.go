@@ -0,0 +1,210 @@
+package info
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Layout of logo and info lines, read from logo_position, logo_padding and
+// logo_gap options
+type layout struct {
+	// position is a position of logo relative to info: left, right, top,
+	// bottom or hidden
+	position string
+
+	// padding is a count of columns before whole output
+	padding int
+
+	// gap is a count of columns between logo and info, or count of lines
+	// if logo is on top or bottom
+	gap int
+}
+
+// Reads layout from options, defaults are logo on the left, no padding and
+// gap of one column or line
+func parseLayout(options map[string]string) (layout, error) {
+	l := layout{position: options["logo_position"], gap: 1}
+
+	switch l.position {
+	case "":
+		l.position = "left"
+
+	case "left", "right", "top", "bottom", "hidden":
+
+	default:
+		return l, fmt.Errorf("%w: logo_position=%v", ErrInvalidOption, l.position)
+	}
+
+	for _, option := range []struct {
+		key   string
+		value *int
+	}{{"logo_padding", &l.padding}, {"logo_gap", &l.gap}} {
+		value, exists := options[option.key]
+		if !exists || value == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			return l, fmt.Errorf("%w: %v=%v", ErrInvalidOption, option.key, value)
+		}
+
+		*option.value = number
+	}
+
+	return l, nil
+}
+
+// Returns true if logo is placed on the side of info lines
+func (l layout) horizontal() bool {
+	return l.position == "left" || l.position == "right"
+}
+
+// Returns true if logo fits terminal width along with info lines, unknown
+// width fits everything
+func (l layout) fits(logo Logo, infoWidth, terminalWidth int) bool {
+	if terminalWidth <= 0 {
+		return true
+	}
+
+	if l.horizontal() {
+		return l.padding+logo.MaxLength+l.gap+infoWidth < terminalWidth
+	}
+
+	return l.padding+logo.MaxLength < terminalWidth
+}
+
+// Composes logo and info lines into output, without trailing newline
+func (l layout) compose(theme Theme, logo Logo, lines []string) string {
+	if l.position == "left" || l.position == "hidden" {
+		return l.composeLeft(theme, logo, lines)
+	}
+
+	return strings.Join(l.lines(theme, logo, lines), "\n")
+}
+
+// Composes logo and info lines into output lines, padding them with spaces
+// instead of moving cursor, so output can be shown outside of terminal.
+// Image logos are not drawn
+func (l layout) lines(theme Theme, logo Logo, lines []string) []string {
+	padding := strings.Repeat(" ", l.padding)
+	art := logoLines(theme, logo)
+	output := []string{}
+
+	if logo.image != "" {
+		art = []string{}
+	}
+
+	switch l.position {
+	case "left", "hidden":
+		for i := 0; i < len(lines) || i < len(art); i++ {
+			line := padding
+
+			if i < len(art) {
+				line += art[i] + repeat(" ", logo.MaxLength-displayWidth(art[i])+l.gap)
+			} else if len(art) > 0 {
+				line += strings.Repeat(" ", logo.MaxLength+l.gap)
+			}
+
+			if i < len(lines) {
+				line += lines[i]
+			}
+
+			output = append(output, strings.TrimRight(line, " "))
+		}
+
+	case "right":
+		infoWidth := maxWidth(lines)
+
+		for i := 0; i < len(lines) || i < len(art); i++ {
+			line := padding
+
+			if i < len(lines) {
+				line += lines[i] + strings.Repeat(" ", infoWidth-displayWidth(lines[i]))
+			} else {
+				line += strings.Repeat(" ", infoWidth)
+			}
+
+			if i < len(art) {
+				line += strings.Repeat(" ", l.gap) + art[i]
+			}
+
+			output = append(output, strings.TrimRight(line, " "))
+		}
+
+	case "top", "bottom":
+		first, second := art, lines
+		if l.position == "bottom" {
+			first, second = lines, art
+		}
+
+		for _, line := range first {
+			output = append(output, padding+line)
+		}
+
+		if len(first) > 0 && len(second) > 0 {
+			output = append(output, make([]string, l.gap)...)
+		}
+
+		for _, line := range second {
+			output = append(output, padding+line)
+		}
+	}
+
+	return output
+}
+
+// Composes logo on the left, drawing it first and moving cursor back up to
+// print info lines on it's right. Image logo is drawn after cursor is moved
+// back, over empty lines reserving space for it
+func (l layout) composeLeft(theme Theme, logo Logo, lines []string) string {
+	// out string
+	var output string
+
+	// offset for printing labels
+	var offset int
+
+	if l.padding > 0 {
+		offset = l.padding + 1
+	}
+
+	if logo.Lines > 0 {
+		padding := strings.Repeat(" ", l.padding)
+
+		output += os.Expand(padding+strings.ReplaceAll(logo.Logo, "\n", "\n"+padding), theme.expand) +
+			strings.Repeat("\x1b[F", logo.Lines-1)
+		offset = l.padding + logo.MaxLength + l.gap + 1
+	}
+
+	if logo.image != "" {
+		output += fmt.Sprintf("\x1b7\x1b[%vG%v\x1b8", l.padding+1, logo.image)
+	}
+
+	var info string
+	for _, line := range lines {
+		info += fmt.Sprintf("\x1b[%vG%v\n", offset, line)
+	}
+
+	output += emptyLinesRegex.ReplaceAllString(info, "")
+
+	if len(lines) < logo.Lines {
+		output += strings.Repeat("\n", logo.Lines-len(lines))
+	}
+
+	return output
+}
+
+// Returns display width of widest line
+func maxWidth(lines []string) int {
+	width := 0
+
+	for _, line := range lines {
+		if lineWidth := displayWidth(line); lineWidth > width {
+			width = lineWidth
+		}
+	}
+
+	return width
+}
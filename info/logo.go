@@ -1,5 +1,15 @@
 package info
 
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
 // Logo type is a struct contains logo and information useful for output
 type Logo struct {
 	// Logo is a logo string
@@ -8,9 +18,245 @@ type Logo struct {
 	// Lines is a logo lines count
 	Lines int
 
-	// MaxLength is a len on longest string in logo
+	// MaxLength is a display width of widest line in logo, in terminal
+	// cells
 	MaxLength int
 
 	// AccentColor is a accent logo color
 	AccentColor string
+
+	// image is an escape sequence drawing image logo, Logo of image logo
+	// is empty lines reserving space for it
+	image string
+}
+
+// Logo without "#accent cN" header error
+var ErrLogoNoAccent = errors.New("logo has no #accent header")
+
+// LogoProblem is a problem found in logo file by LintLogo
+type LogoProblem struct {
+	// Line is a line number, starting from 1
+	Line int
+
+	// Message is a problem description
+	Message string
+}
+
+// Error returns problem as "line N: message"
+func (p LogoProblem) Error() string {
+	return fmt.Sprintf("line %v: %v", p.Line, p.Message)
+}
+
+// Splits logo file contents to accent color and logo itself, CRLF line
+// endings are converted to LF. Newline at the end of file, which editors
+// add, isn't an empty logo line
+func splitLogo(text string) (accent, body string, err error) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	header, body, _ := strings.Cut(text, "\n")
+	body = strings.TrimSuffix(body, "\n")
+
+	accent, found := strings.CutPrefix(header, "#accent ")
+	accent = strings.TrimSpace(accent)
+
+	if !found || accent == "" || strings.ContainsAny(accent, " \t") {
+		return "", "", ErrLogoNoAccent
+	}
+
+	return accent, body, nil
+}
+
+// ParseLogo parses logo file contents: "#accent cN" header followed by logo
+// lines with ${cN} color directives
+func ParseLogo(text string) (Logo, error) {
+	accent, body, err := splitLogo(text)
+	if err != nil {
+		return Logo{}, err
+	}
+
+	logo := Logo{
+		Logo:        body,
+		AccentColor: accent,
+	}
+
+	lines := strings.Split(colorDirectiveRegex.ReplaceAllString(body, ""), "\n")
+	logo.Lines = len(lines)
+
+	for _, line := range lines {
+		width := displayWidth(line)
+		if width > logo.MaxLength {
+			logo.MaxLength = width
+		}
+	}
+
+	return logo, nil
+}
+
+// LintLogo returns problems found in logo file contents, or nil if there
+// are none
+func LintLogo(text string) []LogoProblem {
+	problems := []LogoProblem{}
+
+	if strings.Contains(text, "\r\n") {
+		problems = append(problems, LogoProblem{1, "CRLF line endings, use LF"})
+	}
+
+	accent, body, err := splitLogo(text)
+	if err != nil {
+		return append(problems, LogoProblem{1, err.Error()})
+	}
+
+	if _, exists := lookupColor(accent, Depth256); !exists {
+		problems = append(problems, LogoProblem{1, fmt.Sprintf("unknown accent color %v", accent)})
+	}
+
+	for i, line := range strings.Split(body, "\n") {
+		// header is line 1
+		number := i + 2
+
+		for _, match := range colorDirectiveRegex.FindAllStringSubmatch(line, -1) {
+			if _, exists := lookupColor(match[1], Depth256); !exists {
+				problems = append(problems, LogoProblem{number, fmt.Sprintf("unknown color %v", match[0])})
+			}
+		}
+
+		stripped := colorDirectiveRegex.ReplaceAllString(line, "")
+
+		if strings.Contains(stripped, "$") {
+			problems = append(problems, LogoProblem{number, "$ outside of ${...} directive is expanded"})
+		}
+
+		if strings.Contains(stripped, "\t") {
+			problems = append(problems, LogoProblem{number, "tab character, width is ambiguous"})
+		}
+
+		if strings.ContainsRune(stripped, '\x1b') {
+			problems = append(problems, LogoProblem{number, "raw escape sequence, use ${...} directives"})
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return problems
+}
+
+// LogoDir is a directory of <name>.txt logos
+type LogoDir struct {
+	// Path is a directory path shown to user, "embedded" for built-in logos
+	Path string
+
+	// FS is a directory file system
+	FS fs.FS
+}
+
+// LogoEntry is a logo found in logo directories
+type LogoEntry struct {
+	// Name is a logo name, as used in "logo" option
+	Name string
+
+	// Path is a path of directory logo is found in
+	Path string
+
+	// Shadowed is true if logo with same name is found in directory
+	// searched before
+	Shadowed bool
+}
+
+// DefaultLogoDirs returns logo directories in search order: user logos in
+// $XDG_CONFIG_HOME/barkfetch/logos, system-wide logos in
+// /usr/share/barkfetch/logos and built-in logos. User logos are skipped if
+// there is no config directory
+func DefaultLogoDirs() []LogoDir {
+	embedded, err := fs.Sub(embeddedLogos, "logos")
+	if err != nil {
+		panic(err)
+	}
+
+	dirs := []LogoDir{}
+
+	if config, err := getConfigDir(); err == nil {
+		user := filepath.Join(config, "logos")
+		dirs = append(dirs, LogoDir{Path: user, FS: os.DirFS(user)})
+	}
+
+	system := "/usr/share/barkfetch/logos"
+
+	return append(dirs,
+		LogoDir{Path: system, FS: os.DirFS(system)},
+		LogoDir{Path: "embedded", FS: embedded},
+	)
+}
+
+// Returns true if logo name is a path to logo file instead of logo name
+func isLogoPath(name string) bool {
+	return strings.ContainsRune(name, filepath.Separator) ||
+		strings.HasSuffix(name, ".txt")
+}
+
+// Returns logo file contents by name from first directory it's found in,
+// or by path, along with directory it was found in, or "file" directory
+// without FS for path
+func findLogo(dirs []LogoDir, name string) (text string, dir LogoDir, found bool) {
+	if isLogoPath(name) {
+		bytes, err := os.ReadFile(name)
+		if err != nil {
+			return "", LogoDir{}, false
+		}
+
+		return string(bytes), LogoDir{Path: "file"}, true
+	}
+
+	for _, dir := range dirs {
+		if dir.FS == nil {
+			continue
+		}
+
+		bytes, err := fs.ReadFile(dir.FS, name+".txt")
+		if err == nil {
+			return string(bytes), dir, true
+		}
+	}
+
+	return "", LogoDir{}, false
+}
+
+// Returns true if logo name is found in directories
+func hasLogo(dirs []LogoDir, name string) bool {
+	_, _, found := findLogo(dirs, name)
+	return found
+}
+
+// ListLogos returns every logo found in directories, sorted by name and
+// search order
+func ListLogos(dirs []LogoDir) []LogoEntry {
+	entries := []LogoEntry{}
+	seen := make(map[string]bool)
+
+	for _, dir := range dirs {
+		files, err := fs.Glob(dir.FS, "*.txt")
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name := strings.TrimSuffix(file, ".txt")
+
+			entries = append(entries, LogoEntry{
+				Name:     name,
+				Path:     dir.Path,
+				Shadowed: seen[name],
+			})
+			seen[name] = true
+		}
+	}
+
+	// stable sort keeps search order of same names
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
 }
@@ -0,0 +1,169 @@
+package info
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedLogos(t *testing.T) {
+	// lines and width of logos, counted by hand
+	expected := map[string]struct{ lines, maxLength int }{
+		"almalinux":                 {6, 15},
+		"alpine":                    {5, 12},
+		"android":                   {6, 19},
+		"arch":                      {7, 14},
+		"arch_small":                {5, 10},
+		"arcolinux":                 {7, 15},
+		"artix":                     {7, 14},
+		"centos":                    {7, 11},
+		"debian":                    {6, 9},
+		"deepin":                    {7, 17},
+		"default":                   {12, 21},
+		"default_small":             {7, 10},
+		"elementary":                {6, 13},
+		"endeavouros":               {7, 20},
+		"fedora":                    {8, 16},
+		"freebsd":                   {6, 13},
+		"garuda":                    {7, 21},
+		"gentoo":                    {7, 11},
+		"kali":                      {8, 21},
+		"linuxmint":                 {7, 13},
+		"mac":                       {8, 12},
+		"manjaro":                   {7, 14},
+		"mxlinux":                   {7, 12},
+		"netbsd":                    {7, 20},
+		"nixos":                     {7, 13},
+		"openbsd":                   {7, 15},
+		"opensuse":                  {7, 11},
+		"opensuse-tumbleweed":       {13, 52},
+		"opensuse-tumbleweed_small": {5, 18},
+		"parabola":                  {6, 15},
+		"popos":                     {8, 17},
+		"postmarketos":              {9, 18},
+		"pureos":                    {6, 15},
+		"raspbian":                  {10, 17},
+		"rhel":                      {11, 34},
+		"rhel_small":                {7, 21},
+		"rocky":                     {7, 16},
+		"slackware":                 {7, 13},
+		"solus":                     {6, 14},
+		"ubuntu":                    {6, 11},
+		"void":                      {7, 11},
+		"zorin":                     {6, 13},
+	}
+
+	entries, err := fs.ReadDir(embeddedLogos, "logos")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".txt")
+		seen[name] = true
+
+		t.Run(name, func(t *testing.T) {
+			raw, err := fs.ReadFile(embeddedLogos, path.Join("logos", entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			logo, err := ParseLogo(string(raw))
+			if err != nil {
+				t.Fatalf("ParseLogo: %v", err)
+			}
+
+			if problems := LintLogo(string(raw)); problems != nil {
+				t.Errorf("LintLogo: %v", problems)
+			}
+
+			want, exists := expected[name]
+			if !exists {
+				t.Fatalf("no expected size, add it to test")
+			}
+
+			if logo.Lines != want.lines || logo.MaxLength != want.maxLength {
+				t.Errorf("got %v lines, %v columns, want %v lines, %v columns",
+					logo.Lines, logo.MaxLength, want.lines, want.maxLength)
+			}
+		})
+	}
+
+	for name := range expected {
+		if !seen[name] {
+			t.Errorf("logo %v is missing", name)
+		}
+	}
+}
+
+func TestParseLogoTrailingNewline(t *testing.T) {
+	for _, text := range []string{"#accent c1\nab\ncde", "#accent c1\nab\ncde\n", "#accent c1\r\nab\r\ncde\r\n"} {
+		logo, err := ParseLogo(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if logo.Lines != 2 || logo.MaxLength != 3 || logo.Logo != "ab\ncde" {
+			t.Errorf("%q: got %+v", text, logo)
+		}
+	}
+}
+
+func TestFitLogoSmallFromSameDir(t *testing.T) {
+	user := fstest.MapFS{"arch.txt": {Data: []byte("#accent c1\nuser arch")}}
+	embedded := fstest.MapFS{
+		"arch.txt":       {Data: []byte("#accent c1\nembedded arch")},
+		"arch_small.txt": {Data: []byte("#accent c1\nsmall")},
+	}
+
+	r := &Renderer{Logos: []LogoDir{{Path: "user", FS: user}, {Path: "embedded", FS: embedded}}}
+
+	name, dir, logo, err := r.getLogo("arch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dir.Path != "user" {
+		t.Fatalf("read %v from %v, want user", name, dir.Path)
+	}
+
+	fitted, err := r.fitLogo(name, dir, logo, "small", func(Logo) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fitted.Logo != "user arch" {
+		t.Errorf("small variant of user logo is %q, want user logo kept", fitted.Logo)
+	}
+
+	fitted, err = r.fitLogo(name, r.Logos[1], logo, "small", func(Logo) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fitted.Logo != "small" {
+		t.Errorf("small variant of embedded logo is %q, want %q", fitted.Logo, "small")
+	}
+}
+
+func TestGetLogoMissing(t *testing.T) {
+	embedded := fstest.MapFS{"default.txt": {Data: []byte("#accent c1\ndefault")}}
+	r := &Renderer{Logos: []LogoDir{{Path: "embedded", FS: embedded}}}
+
+	for _, path := range []string{"/no/such/file.txt", "./no-such-logo.txt"} {
+		_, _, _, err := r.getLogo(path)
+		if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), path) {
+			t.Errorf("%v: got error %v, want not exist error naming path", path, err)
+		}
+	}
+
+	name, _, logo, err := r.getLogo("no-such-distro")
+	if err != nil || name != "default" || logo.Logo != "default" {
+		t.Errorf("missing name: got %v, %q, %v, want default logo", name, logo.Logo, err)
+	}
+}
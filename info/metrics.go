@@ -0,0 +1,142 @@
+package info
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Modules probed for metrics, whether they are displayed or not
+var metricsModules = []string{"userline", "os", "kernel", "uptime", "shell",
+	"cpu", "gpu", "memory", "swap", "disk", "battery"}
+
+// Content type of Prometheus text exposition format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Escapes Prometheus label value
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter writes metrics in Prometheus text exposition format, every
+// metric family is written once with it's help and type
+type metricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Writes metric family header
+func (m *metricsWriter) family(name, help string) {
+	m.printf("# HELP %v %v\n# TYPE %v gauge\n", name, help, name)
+}
+
+// Writes metric sample with labels as alternating names and values
+func (m *metricsWriter) sample(name string, value float64, labels ...string) {
+	pairs := []string{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, labels[i], labelValueReplacer.Replace(labels[i+1])))
+	}
+
+	if len(pairs) > 0 {
+		name += "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	m.printf("%v %v\n", name, value)
+}
+
+// Writes formatted text, keeping first error
+func (m *metricsWriter) printf(format string, args ...any) {
+	if m.err != nil {
+		return
+	}
+
+	_, m.err = fmt.Fprintf(m.w, format, args...)
+}
+
+// WriteMetrics collects info and writes it as Prometheus metrics: memory,
+// swap and disk usage in bytes, uptime in seconds, battery charge ratio and
+// text facts, like OS and CPU model, as labels of info metrics. Modules are
+// probed whether they are enabled in options or not
+func (r *Renderer) WriteMetrics(w io.Writer, options map[string]string) error {
+	metricsOptions := make(map[string]string, len(options))
+	for key, value := range options {
+		metricsOptions[key] = value
+	}
+
+	for _, module := range metricsModules {
+		metricsOptions[module] = "true"
+	}
+
+	info, err := r.Collect(metricsOptions)
+	if err != nil {
+		return err
+	}
+
+	m := &metricsWriter{w: w}
+
+	system := OS{}
+	if info.OS != nil {
+		system = *info.OS
+	}
+
+	m.family("barkfetch_info", "System facts as labels, value is always 1.")
+	m.sample("barkfetch_info", 1,
+		"host", info.Host,
+		"os", system.Name,
+		"arch", system.Arch,
+		"kernel", info.Kernel,
+		"cpu", info.CPU,
+		"shell", info.Shell,
+	)
+
+	if len(info.GPUs) > 0 {
+		m.family("barkfetch_gpu_info", "GPU manufacturer and model as label, value is always 1.")
+
+		gpus := append([]string{}, info.GPUs...)
+		sort.Strings(gpus)
+
+		for _, gpu := range gpus {
+			m.sample("barkfetch_gpu_info", 1, "gpu", gpu)
+		}
+	}
+
+	if info.Uptime != nil {
+		m.family("barkfetch_uptime_seconds", "System uptime in seconds.")
+		m.sample("barkfetch_uptime_seconds", float64(info.Uptime.Total))
+	}
+
+	for _, memory := range []struct {
+		name   string
+		memory *Memory
+	}{{"memory", info.Memory}, {"swap", info.Swap}} {
+		if memory.memory == nil {
+			continue
+		}
+
+		m.family("barkfetch_"+memory.name+"_used_bytes", "Used "+memory.name+" in bytes.")
+		m.sample("barkfetch_"+memory.name+"_used_bytes", float64(memory.memory.Used))
+		m.family("barkfetch_"+memory.name+"_total_bytes", "Total "+memory.name+" in bytes.")
+		m.sample("barkfetch_"+memory.name+"_total_bytes", float64(memory.memory.Total))
+	}
+
+	if len(info.Disks) > 0 {
+		m.family("barkfetch_disk_used_bytes", "Used space of file system in bytes.")
+		for _, disk := range info.Disks {
+			m.sample("barkfetch_disk_used_bytes", float64(disk.Used), "mount", disk.Mount)
+		}
+
+		m.family("barkfetch_disk_total_bytes", "Used and available space of file system in bytes.")
+		for _, disk := range info.Disks {
+			m.sample("barkfetch_disk_total_bytes", float64(disk.Total), "mount", disk.Mount)
+		}
+	}
+
+	if len(info.Batteries) > 0 {
+		m.family("barkfetch_battery_charge_ratio", "Battery charge, from 0 to 1.")
+		for _, battery := range info.Batteries {
+			m.sample("barkfetch_battery_charge_ratio", float64(battery.Percent)/100,
+				"battery", battery.Name, "status", battery.Status)
+		}
+	}
+
+	return m.err
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package info
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Scrape of debian fixture, with shell label escaped
+const debianMetrics = `# HELP barkfetch_info System facts as labels, value is always 1.
+# TYPE barkfetch_info gauge
+barkfetch_info{host="deb-desktop",os="Debian GNU/Linux 12 (bookworm)",arch="amd64",kernel="Linux 6.1.0-13-amd64",cpu="12th Gen Intel(R) Core(TM) i7-1260P",shell="/opt/\"odd\"\\shell\nv2"} 1
+# HELP barkfetch_gpu_info GPU manufacturer and model as label, value is always 1.
+# TYPE barkfetch_gpu_info gauge
+barkfetch_gpu_info{gpu="Intel Alder Lake-P Integrated Graphics Controller"} 1
+barkfetch_gpu_info{gpu="NVIDIA GeForce RTX 3050 Mobile"} 1
+# HELP barkfetch_uptime_seconds System uptime in seconds.
+# TYPE barkfetch_uptime_seconds gauge
+barkfetch_uptime_seconds 93784
+# HELP barkfetch_memory_used_bytes Used memory in bytes.
+# TYPE barkfetch_memory_used_bytes gauge
+barkfetch_memory_used_bytes 4.725464064e+09
+# HELP barkfetch_memory_total_bytes Total memory in bytes.
+# TYPE barkfetch_memory_total_bytes gauge
+barkfetch_memory_total_bytes 1.6710053888e+10
+# HELP barkfetch_swap_used_bytes Used swap in bytes.
+# TYPE barkfetch_swap_used_bytes gauge
+barkfetch_swap_used_bytes 5.36870912e+08
+# HELP barkfetch_swap_total_bytes Total swap in bytes.
+# TYPE barkfetch_swap_total_bytes gauge
+barkfetch_swap_total_bytes 2.147479552e+09
+# HELP barkfetch_disk_used_bytes Used space of file system in bytes.
+# TYPE barkfetch_disk_used_bytes gauge
+barkfetch_disk_used_bytes{mount="/"} 1.26419751936e+11
+barkfetch_disk_used_bytes{mount="/mnt/My Data"} 4.91673624576e+11
+# HELP barkfetch_disk_total_bytes Used and available space of file system in bytes.
+# TYPE barkfetch_disk_total_bytes gauge
+barkfetch_disk_total_bytes{mount="/"} 4.7726617088e+11
+barkfetch_disk_total_bytes{mount="/mnt/My Data"} 9.83347249152e+11
+# HELP barkfetch_battery_charge_ratio Battery charge, from 0 to 1.
+# TYPE barkfetch_battery_charge_ratio gauge
+barkfetch_battery_charge_ratio{battery="BAT0",status="discharging"} 0.87
+`
+
+func TestMetricsScrape(t *testing.T) {
+	system := fixtureSystem("debian-12-x86_64")
+	system.Getenv = func(key string) string {
+		if key == "SHELL" {
+			return "/opt/\"odd\"\\shell\nv2"
+		}
+
+		return ""
+	}
+
+	renderer := &Renderer{System: system}
+	options := map[string]string{"cache": "false", "disk_mounts": "/"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MetricsContentType)
+
+		if err := renderer.WriteMetrics(w, options); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType := response.Header.Get("Content-Type"); contentType != MetricsContentType {
+		t.Errorf("content type is %q", contentType)
+	}
+
+	if string(body) != debianMetrics {
+		t.Errorf("got metrics:\n%s\nwant:\n%s", body, debianMetrics)
+	}
+}
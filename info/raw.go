@@ -6,50 +6,139 @@ import (
 	"fmt"
 	"io"
 	"net"
-	"net/http"
-	"os"
-	"regexp"
-	"runtime"
+	"strconv"
 	"strings"
 )
 
-// Useful regexes
-var (
-	replaceDirectivesRegex = regexp.MustCompile(`(?:#accent c\d{1,2}\n|\$\{c(?:\d{1,2}|reset)\})`)
-	getAccentRegex         = regexp.MustCompile(`#accent (c\d{1,2})`)
-	getLogoRegex           = regexp.MustCompile(`#accent c\d{1,2}\n([\S\s]*)`)
-)
+// Gets username from program environment, falls back to current user and
+// effective user ID lookup in /etc/passwd, as USER is unset under cron,
+// services and containers
+func (s *System) getRawUser() string {
+	for _, key := range []string{"USER", "LOGNAME"} {
+		if user := s.Getenv(key); user != "" {
+			return user
+		}
+	}
+
+	current, err := s.CurrentUser()
+	if err == nil && current.Username != "" {
+		return current.Username
+	}
+
+	if name, _, found := s.lookupPasswd(s.Geteuid()); found {
+		return name
+	}
 
-// Gets username from program environment
-func getRawUser() string {
-	return os.Getenv("USER")
+	return "n/a"
 }
 
-// Gets system hostname
-func getRawHostname() string {
-	hostname, err := os.Hostname()
+// Gets full name of current user from GECOS field, empty if it's unknown
+func (s *System) getRawUserName() string {
+	current, err := s.CurrentUser()
+	if err == nil && current.Name != "" {
+		return current.Name
+	}
+
+	_, gecos, _ := s.lookupPasswd(s.Geteuid())
+
+	// full name is first of comma-separated GECOS fields
+	name, _, _ := strings.Cut(gecos, ",")
+	return name
+}
+
+// Gets real user running barkfetch under sudo, doas or setuid, empty if
+// real user is effective one
+func (s *System) getRawRealUser() string {
+	for _, key := range []string{"SUDO_USER", "DOAS_USER"} {
+		if user := s.Getenv(key); user != "" {
+			return user
+		}
+	}
+
+	if uid := s.Getuid(); uid != s.Geteuid() {
+		if name, _, found := s.lookupPasswd(uid); found {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Gets fully qualified domain name of host, empty if it's unknown
+func (s *System) getRawFQDN() string {
+	out, err := s.output("hostname", "-f")
 	if err != nil {
-		return "n/a"
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// passwdFS is a file system looking users up in /etc/passwd itself, like
+// recordingFS, which records only line of found user
+type passwdFS interface {
+	lookupPasswd(uid int) (line string, found bool)
+}
+
+// Looks user up by ID in /etc/passwd, returns it's name and GECOS field
+func (s *System) lookupPasswd(uid int) (name, gecos string, found bool) {
+	if uid < 0 {
+		return "", "", false
+	}
+
+	var line string
+
+	if lookup, ok := s.Root.(passwdFS); ok {
+		line, found = lookup.lookupPasswd(uid)
+	} else {
+		raw, err := s.readFile("/etc/passwd")
+		if err != nil {
+			return "", "", false
+		}
+
+		line, found = findPasswdLine(string(raw), uid)
+	}
+
+	if !found {
+		return "", "", false
+	}
+
+	fields := strings.Split(line, ":")
+	return fields[0], fields[4], true
+}
+
+// Returns line of user ID in /etc/passwd contents, lines are
+// "name:password:uid:gid:gecos:home:shell"
+func findPasswdLine(passwd string, uid int) (string, bool) {
+	for _, line := range strings.Split(passwd, "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) >= 7 && fields[2] == strconv.Itoa(uid) {
+			return line, true
+		}
 	}
 
-	return hostname
+	return "", false
 }
 
 // Get local ip
-func getRawLocalIp() string {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+func (s *System) getRawLocalIp() string {
+	conn, err := s.Dial("udp", "8.8.8.8:80")
 	if err != nil {
 		return "n/a"
 	}
 	defer conn.Close()
 
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return "n/a"
+	}
+
 	return localAddr.IP.String()
 }
 
 // Get outbound ip
-func getRawOutboundIp() string {
-	resp, err := http.Get("https://api.ipify.org?format=text")
+func (s *System) getRawOutboundIp() string {
+	resp, err := s.Client.Get("https://api.ipify.org?format=text")
 	if err != nil {
 		return "n/a"
 	}
@@ -63,9 +152,51 @@ func getRawOutboundIp() string {
 	return string(ip)
 }
 
+// Returns usage of file systems mounted on mounts from "df" output, in
+// order of mounts
+func (s *System) getRawDisks(mounts []string) []Disk {
+	out, err := s.output("df", append([]string{"-Pk"}, mounts...)...)
+	if err != nil && len(out) == 0 {
+		return []Disk{}
+	}
+
+	disks := []Disk{}
+	seen := map[string]bool{}
+
+	// header is skipped, fields are file system, total, used and
+	// available kilobytes, capacity and mount point
+	for _, line := range strings.Split(string(out), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+
+		used, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		available, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil || used+available == 0 {
+			continue
+		}
+
+		// paths on same file system are reported once
+		mount := strings.Join(fields[5:], " ")
+		if seen[mount] {
+			continue
+		}
+		seen[mount] = true
+
+		disks = append(disks, newDisk(mount, used*1024, (used+available)*1024))
+	}
+
+	return disks
+}
+
 // Gets OS architecture
-func getRawArchitecture() string {
-	return runtime.GOARCH
+func (s *System) getRawArchitecture() string {
+	return s.Arch
 }
 
 // Get colors table
@@ -90,43 +221,5 @@ var (
 	_default string
 
 	//go:embed logos/*
-	logos embed.FS
+	embeddedLogos embed.FS
 )
-
-// Returns distro logo by name, or guesses if arg is "auto"
-// Currently return small count of logos, mostly default
-func getLogo(distro string) Logo {
-	logoText := _default
-
-	if distro == "auto" {
-		return getLogo(guessDistro())
-	}
-
-	bytes, err := logos.ReadFile(fmt.Sprintf("logos/%v.txt", distro))
-	if err == nil {
-		logoText = string(bytes)
-	}
-
-	var logo Logo
-
-	match := getLogoRegex.FindStringSubmatch(logoText)
-	logo.Logo = match[1]
-
-	match = getAccentRegex.FindStringSubmatch(logoText)
-	logo.AccentColor = match[1]
-
-	directiveFreeLogoText := replaceDirectivesRegex.ReplaceAllString(logoText, "")
-	logo.Lines = len(strings.Split(directiveFreeLogoText, "\n"))
-
-	max := 0
-
-	for _, line := range strings.Split(directiveFreeLogoText, "\n") {
-		if len(line) > max {
-			max = len(line)
-		}
-	}
-
-	logo.MaxLength = max
-
-	return logo
-}
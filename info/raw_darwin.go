@@ -4,10 +4,9 @@ package info
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,6 +20,12 @@ var (
 	extractCompressedMemoryRegex = regexp.MustCompile(`Pages occupied by compressor:\s+(\d+)\.`)
 )
 
+// Extracts used and total swap from "sysctl -n vm.swapusage"
+var extractSwapRegex = regexp.MustCompile(`total = ([\d.]+)M\s+used = ([\d.]+)M`)
+
+// Extracts batteries charge from "pmset -g batt"
+var extractBatteryRegex = regexp.MustCompile(`(?m)^\s*-(\S+).*?\t(\d+)%; ([^;]+);`)
+
 // Extract GPU model from "system_profiler SPDisplaysDataType"
 var extractChipsetModelRegex = regexp.MustCompile(`Chipset Model: (.*)`)
 
@@ -28,8 +33,8 @@ var extractChipsetModelRegex = regexp.MustCompile(`Chipset Model: (.*)`)
 var extractResolutionRegex = regexp.MustCompile(`(?m)^\s+Resolution: (\d+) x (\d+)$`)
 
 // Returns OS kernel type and it's version
-func getRawKernel() string {
-	out, err := exec.Command("uname", "-r").Output()
+func (s *System) getRawKernel() string {
+	out, err := s.output("uname", "-r")
 	if err != nil {
 		return "n/a"
 	}
@@ -38,8 +43,8 @@ func getRawKernel() string {
 }
 
 // Returns system uptime in seconds
-func getRawUptime() int64 {
-	out, err := exec.Command("sysctl", "kern.boottime").Output()
+func (s *System) getRawUptime() int64 {
+	out, err := s.output("sysctl", "kern.boottime")
 	if err != nil {
 		return -1
 	}
@@ -56,26 +61,34 @@ func getRawUptime() int64 {
 	return time.Now().Add(time.Duration(-seconds * int64(time.Second))).Unix()
 }
 
+// Gets system hostname
+func (s *System) getRawHostname() string {
+	out, err := s.output("hostname")
+	if err != nil {
+		return "n/a"
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
 // Returns used shell
-func getRawShell() string {
-	return os.Getenv("SHELL")
+func (s *System) getRawShell() string {
+	return s.Getenv("SHELL")
 }
 
-// Returns used and total memory in megabytes
-func getRawMemory() (used, total int) {
-	totalMemoryString, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+// Returns used and total memory in bytes
+func (s *System) getRawMemory() (used, total uint64) {
+	totalMemoryString, err := s.output("sysctl", "-n", "hw.memsize")
 	if err != nil {
 		return
 	}
 
-	// newline hack
-	totalMemoryString = totalMemoryString[:len(totalMemoryString)-1]
-	totalMemory, err := strconv.ParseInt(string(totalMemoryString), 10, 64)
+	totalMemory, err := strconv.ParseInt(strings.TrimSpace(string(totalMemoryString)), 10, 64)
 	if err != nil {
 		return
 	}
 
-	vmStat, err := exec.Command("vm_stat").Output()
+	vmStat, err := s.output("vm_stat")
 	if err != nil {
 		return
 	}
@@ -107,25 +120,25 @@ func getRawMemory() (used, total int) {
 		return
 	}
 
-	total = int(totalMemory / 1000000)
-	used = int((wired + active + compressed) * 4 / 1024)
+	total = uint64(totalMemory)
+	used = uint64((wired + active + compressed) * 4096)
 
 	return
 }
 
 // Returns CPU model (currently first)
-func getRawCpu() string {
-	out, err := exec.Command("sysctl", "-n", "machdep.cpu.brand_string").Output()
+func (s *System) getRawCpu() string {
+	out, err := s.output("sysctl", "-n", "machdep.cpu.brand_string")
 	if err != nil {
 		return "n/a"
 	}
 
-	return string(out[:len(out)-1])
+	return strings.TrimSpace(string(out))
 }
 
 // Returns GPU manufacturer and model
-func getRawGpus() []string {
-	out, err := exec.Command("system_profiler", "SPDisplaysDataType").Output()
+func (s *System) getRawGpus() []string {
+	out, err := s.output("system_profiler", "SPDisplaysDataType")
 	if err != nil {
 		return []string{}
 	}
@@ -139,8 +152,8 @@ func getRawGpus() []string {
 }
 
 // Returns main screen resolution
-func getRawScreenResolutions() []string {
-	out, err := exec.Command("system_profiler", "SPDisplaysDataType").Output()
+func (s *System) getRawScreenResolutions() []string {
+	out, err := s.output("system_profiler", "SPDisplaysDataType")
 	if err != nil {
 		return []string{}
 	}
@@ -155,18 +168,76 @@ func getRawScreenResolutions() []string {
 }
 
 // Returns OS pretty name
-func getRawPrettyName() string {
-	out, err := exec.Command("sw_vers", "-productVersion").Output()
+func (s *System) getRawPrettyName() string {
+	out, err := s.output("sw_vers", "-productVersion")
 	if err != nil {
 		return "n/a"
 	}
 
-	// newline hack
-	out = out[:len(out)-1]
-	return fmt.Sprintf("macOS %v", string(out))
+	return fmt.Sprintf("macOS %v", strings.TrimSpace(string(out)))
 }
 
 // Returns "mac" always lol
-func guessDistro() string {
-	return "mac"
+func (s *System) guessDistros() []string {
+	return []string{"mac"}
+}
+
+// Returns unique ID of current boot
+func (s *System) getRawBootId() string {
+	out, err := s.output("sysctl", "-n", "kern.bootsessionuuid")
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// Returns used and total swap in bytes
+func (s *System) getRawSwap() (used, total uint64) {
+	out, err := s.output("sysctl", "-n", "vm.swapusage")
+	if err != nil {
+		return
+	}
+
+	match := extractSwapRegex.FindStringSubmatch(string(out))
+	if len(match) == 0 {
+		return
+	}
+
+	totalSwap, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return
+	}
+
+	usedSwap, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return
+	}
+
+	return uint64(usedSwap * (1 << 20)), uint64(totalSwap * (1 << 20))
+}
+
+// Returns batteries charge
+func (s *System) getRawBatteries() []Battery {
+	out, err := s.output("pmset", "-g", "batt")
+	if err != nil {
+		return []Battery{}
+	}
+
+	batteries := []Battery{}
+
+	for _, match := range extractBatteryRegex.FindAllStringSubmatch(string(out), -1) {
+		percent, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+
+		batteries = append(batteries, Battery{
+			Name:    match[1],
+			Percent: percent,
+			Status:  strings.TrimSpace(match[3]),
+		})
+	}
+
+	return batteries
 }
@@ -4,12 +4,9 @@ package info
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
-	"syscall"
 )
 
 // Regexes used for extraction memory info from /proc/meminfo
@@ -20,13 +17,23 @@ var (
 	getBuffersRegex      = regexp.MustCompile(`Buffers:\s+(\d+) kB`)
 	getCachedRegex       = regexp.MustCompile(`Cached:\s+(\d+) kB`)
 	getSReclaimableRegex = regexp.MustCompile(`SReclaimable:\s+(\d+) kB`)
+	getSwapTotalRegex    = regexp.MustCompile(`SwapTotal:\s+(\d+) kB`)
+	getSwapFreeRegex     = regexp.MustCompile(`SwapFree:\s+(\d+) kB`)
 	getIdRegex           = regexp.MustCompile(`(?m)^ID=\"?([^\"]*?)\"?$`)
+	getIdLikeRegex       = regexp.MustCompile(`(?m)^ID_LIKE=\"?([^\"]*?)\"?$`)
 	getPrettyNameRegex   = regexp.MustCompile(`(?m)^PRETTY_NAME=\"?([^\"]*?)\"?$`)
 )
 
 // Regex used to extract CPU model from /proc/cpuinfo
 var getCpuModelRegex = regexp.MustCompile(`(?m)^model name\s+: (.*)$`)
 
+// Regexes used to extract board model and SoC from /proc/cpuinfo of ARM
+// boards, which have no "model name"
+var (
+	getBoardModelRegex = regexp.MustCompile(`(?m)^Model\s+: (.*)$`)
+	getHardwareRegex   = regexp.MustCompile(`(?m)^Hardware\s+: (.*)$`)
+)
+
 // Regex used to extract raw GPU manufacturer and model from "lspci" output
 var getRawGpuManufacturerAndModelRegex = regexp.MustCompile(`.*"(?:Display|3D|VGA).*?" "(.*?)" "(.*?)"`)
 
@@ -41,56 +48,54 @@ var getResolutionRegex = regexp.MustCompile(`connected(?: primary)? (\d+)x(\d+)`
 // Regex used to remove too much spaces between words
 var removeExtraSpacesRegex = regexp.MustCompile(`\s+`)
 
-// Convert array of (u)int8 to string
-func int8ToStr(arr []int) string {
-	b := make([]byte, 0, len(arr))
-	for _, v := range arr {
-		if v == 0x00 {
-			break
-		}
-		b = append(b, byte(v))
+// Returns OS kernel type and it's version
+func (s *System) getRawKernel() string {
+	raw, err := s.readFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return "n/a"
 	}
-	return string(b)
-}
 
-// Returns OS kernel type and it's version
-func getRawKernel() string {
-	var info syscall.Utsname
+	return fmt.Sprintf("Linux %v", strings.TrimSpace(string(raw)))
+}
 
-	err := syscall.Uname(&info)
+// Returns system uptime in seconds
+func (s *System) getRawUptime() int {
+	raw, err := s.readFile("/proc/uptime")
 	if err != nil {
-		return "n/a"
+		return -1
 	}
 
-	intArr := []int{}
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return -1
+	}
 
-	for _, val := range info.Release {
-		intArr = append(intArr, int(val))
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return -1
 	}
 
-	return fmt.Sprintf("Linux %v", int8ToStr(intArr))
+	return int(uptime)
 }
 
-// Returns system uptime in seconds
-func getRawUptime() int {
-	var info syscall.Sysinfo_t
-
-	err := syscall.Sysinfo(&info)
+// Gets system hostname
+func (s *System) getRawHostname() string {
+	raw, err := s.readFile("/proc/sys/kernel/hostname")
 	if err != nil {
-		return -1
+		return "n/a"
 	}
 
-	return int(info.Uptime)
+	return strings.TrimSpace(string(raw))
 }
 
 // Returns used shell
-func getRawShell() string {
-	return os.Getenv("SHELL")
+func (s *System) getRawShell() string {
+	return s.Getenv("SHELL")
 }
 
-// Returns used and total memory in megabytes
-func getRawMemory() (used, total int) {
-	raw, err := os.ReadFile("/proc/meminfo")
+// Returns used and total memory in bytes
+func (s *System) getRawMemory() (used, total uint64) {
+	raw, err := s.readFile("/proc/meminfo")
 	if err != nil {
 		return
 	}
@@ -157,31 +162,98 @@ func getRawMemory() (used, total int) {
 	// 	return
 	// }
 
-	total = totalMem / 1000
-	used = (totalMem + shMem - freeMem - buffers - cached - sReclaimable) / 1000
+	total = uint64(totalMem) * 1024
+	used = uint64(totalMem+shMem-freeMem-buffers-cached-sReclaimable) * 1024
 
 	return
 }
 
-// Returns CPU model (currently first)
-func getRawCpu() string {
-	raw, err := os.ReadFile("/proc/cpuinfo")
+// Returns used and total swap in bytes
+func (s *System) getRawSwap() (used, total uint64) {
+	raw, err := s.readFile("/proc/meminfo")
 	if err != nil {
-		return "n/a"
+		return
 	}
 
 	contents := string(raw)
-	match := getCpuModelRegex.FindStringSubmatch(contents)
+
+	match := getSwapTotalRegex.FindStringSubmatch(contents)
 	if len(match) == 0 {
-		return "n/a"
+		return
+	}
+	totalSwap, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	match = getSwapFreeRegex.FindStringSubmatch(contents)
+	if len(match) == 0 {
+		return
+	}
+	freeSwap, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil || freeSwap > totalSwap {
+		return
+	}
+
+	return (totalSwap - freeSwap) * 1024, totalSwap * 1024
+}
+
+// Returns batteries charge from /sys/class/power_supply, trying BAT0-3 as
+// power supplies can't be listed on recorded system
+func (s *System) getRawBatteries() []Battery {
+	batteries := []Battery{}
+
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("BAT%v", i)
+
+		capacity, err := s.readFile("/sys/class/power_supply/" + name + "/capacity")
+		if err != nil {
+			continue
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSpace(string(capacity)))
+		if err != nil {
+			continue
+		}
+
+		status, _ := s.readFile("/sys/class/power_supply/" + name + "/status")
+
+		batteries = append(batteries, Battery{
+			Name:    name,
+			Percent: percent,
+			Status:  strings.ToLower(strings.TrimSpace(string(status))),
+		})
+	}
+
+	return batteries
+}
+
+// Returns CPU model (currently first), or board model on ARM boards
+func (s *System) getRawCpu() string {
+	raw, err := s.readFile("/proc/cpuinfo")
+	if err == nil {
+		contents := string(raw)
+
+		for _, regex := range []*regexp.Regexp{getCpuModelRegex, getBoardModelRegex, getHardwareRegex} {
+			match := regex.FindStringSubmatch(contents)
+			if len(match) != 0 {
+				return removeExtraSpacesRegex.ReplaceAllString(strings.TrimSpace(match[1]), " ")
+			}
+		}
 	}
 
-	return removeExtraSpacesRegex.ReplaceAllString(match[1], " ")
+	// device tree has model of ARM boards, which cpuinfo may lack
+	raw, err = s.readFile("/proc/device-tree/model")
+	if model := strings.Trim(string(raw), "\x00\n "); err == nil && model != "" {
+		return model
+	}
+
+	return "n/a"
 }
 
 // Returns GPU manufacturer and model
-func getRawGpus() []string {
-	out, err := exec.Command("lspci", "-mm").Output()
+func (s *System) getRawGpus() []string {
+	out, err := s.output("lspci", "-mm")
 	if err != nil {
 		return []string{}
 	}
@@ -208,18 +280,23 @@ func getRawGpus() []string {
 			manufacturer = "AMD"
 		}
 
+		model = line[2]
+
 		modelMatch := getGpuModelRegex.FindStringSubmatch(line[2])
-		model = modelMatch[1]
+		if len(modelMatch) != 0 {
+			model = modelMatch[1]
+		}
 
-		gpus = append(gpus, fmt.Sprintf("%v %v", manufacturer, model))
+		// manufacturer is unknown for other vendors
+		gpus = append(gpus, strings.TrimSpace(fmt.Sprintf("%v %v", manufacturer, model)))
 	}
 
 	return gpus
 }
 
 // Returns main screen resolution
-func getRawScreenResolutions() []string {
-	out, err := exec.Command("xrandr", "--nograb", "--current").Output()
+func (s *System) getRawScreenResolutions() []string {
+	out, err := s.output("xrandr", "--nograb", "--current")
 	if err != nil {
 		return []string{}
 	}
@@ -240,8 +317,8 @@ func getRawScreenResolutions() []string {
 }
 
 // Returns OS pretty name
-func getRawPrettyName() string {
-	raw, err := os.ReadFile("/etc/os-release")
+func (s *System) getRawPrettyName() string {
+	raw, err := s.readFile("/etc/os-release")
 	if err != nil {
 		return "n/a"
 	}
@@ -255,18 +332,36 @@ func getRawPrettyName() string {
 	return match[1]
 }
 
-// Guesses distro by /etc/os-release values
-func guessDistro() string {
-	raw, err := os.ReadFile("/etc/os-release")
+// Guesses distro by /etc/os-release values, returns ID followed by
+// ID_LIKE values, most specific first
+func (s *System) guessDistros() []string {
+	raw, err := s.readFile("/etc/os-release")
 	if err != nil {
-		return "n/a"
+		return []string{}
 	}
 
 	contents := string(raw)
 	match := getIdRegex.FindStringSubmatch(contents)
 	if len(match) == 0 {
-		return "n/a"
+		return []string{}
 	}
 
-	return match[1]
+	distros := []string{match[1]}
+
+	match = getIdLikeRegex.FindStringSubmatch(contents)
+	if len(match) != 0 {
+		distros = append(distros, strings.Fields(match[1])...)
+	}
+
+	return distros
+}
+
+// Returns unique ID of current boot
+func (s *System) getRawBootId() string {
+	raw, err := s.readFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(raw))
 }
@@ -0,0 +1,389 @@
+//go:build linux
+
+package info
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os/user"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Fixtures in testdata, recorded from real machines and trimmed
+var fixtures = []string{
+	"debian-12-x86_64",
+	"rocky-9-x86_64",
+	"raspios-aarch64",
+	"armbian-aarch64",
+	"alpine-container",
+}
+
+// Runs test for every fixture, with expected value of fixture
+func forFixtures[T any](t *testing.T, expected map[string]T, probe func(s *System) T) {
+	t.Helper()
+
+	for _, fixture := range fixtures {
+		want, exists := expected[fixture]
+		if !exists {
+			t.Fatalf("no expected value for fixture %v", fixture)
+		}
+
+		t.Run(fixture, func(t *testing.T) {
+			got := probe(fixtureSystem(fixture))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestGetRawKernel(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "Linux 6.1.0-13-amd64",
+		"rocky-9-x86_64":   "Linux 5.14.0-362.8.1.el9_3.x86_64",
+		"raspios-aarch64":  "Linux 6.1.21-v8+",
+		"armbian-aarch64":  "Linux 6.1.50-current-sunxi64",
+		"alpine-container": "Linux 6.5.0-1015-azure",
+	}, (*System).getRawKernel)
+}
+
+func TestGetRawUptime(t *testing.T) {
+	forFixtures(t, map[string]int{
+		"debian-12-x86_64": 93784,
+		"rocky-9-x86_64":   3599,
+		"raspios-aarch64":  59,
+		"armbian-aarch64":  1234567,
+		"alpine-container": -1,
+	}, (*System).getRawUptime)
+}
+
+func TestGetRawHostname(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "deb-desktop",
+		"rocky-9-x86_64":   "rocky",
+		"raspios-aarch64":  "raspberrypi",
+		"armbian-aarch64":  "orangepi3-lts",
+		"alpine-container": "3f2a1b0c9d8e",
+	}, (*System).getRawHostname)
+}
+
+func TestGetRawMemory(t *testing.T) {
+	forFixtures(t, map[string][2]uint64{
+		"debian-12-x86_64": {
+			(16318412 + 345678 - 8123456 - 234567 - 3456789 - 234567) * 1024,
+			16318412 * 1024,
+		},
+		"rocky-9-x86_64": {
+			(8008848 + 12345 - 5123456 - 4096 - 1234567 - 98765) * 1024,
+			8008848 * 1024,
+		},
+		"raspios-aarch64": {
+			(3884192 + 23456 - 2912345 - 45678 - 456789 - 34567) * 1024,
+			3884192 * 1024,
+		},
+		"armbian-aarch64": {
+			(4012345 + 4567 - 3012345 - 12345 - 234567 - 23456) * 1024,
+			4012345 * 1024,
+		},
+		// container meminfo lacks fields memory usage is counted from
+		"alpine-container": {0, 0},
+	}, func(s *System) [2]uint64 {
+		used, total := s.getRawMemory()
+		return [2]uint64{used, total}
+	})
+}
+
+func TestGetRawSwap(t *testing.T) {
+	forFixtures(t, map[string][2]uint64{
+		"debian-12-x86_64": {(2097148 - 1572860) * 1024, 2097148 * 1024},
+		"rocky-9-x86_64":   {0, 0},
+		"raspios-aarch64":  {0, 102396 * 1024},
+		"armbian-aarch64":  {0, 2006172 * 1024},
+		"alpine-container": {0, 0},
+	}, func(s *System) [2]uint64 {
+		used, total := s.getRawSwap()
+		return [2]uint64{used, total}
+	})
+}
+
+func TestGetRawBatteries(t *testing.T) {
+	forFixtures(t, map[string][]Battery{
+		"debian-12-x86_64": {{Name: "BAT0", Percent: 87, Status: "discharging"}},
+		"rocky-9-x86_64":   {},
+		"raspios-aarch64":  {},
+		"armbian-aarch64":  {},
+		"alpine-container": {},
+	}, (*System).getRawBatteries)
+}
+
+func TestGetRawCpu(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "12th Gen Intel(R) Core(TM) i7-1260P",
+		"rocky-9-x86_64":   "AMD EPYC 7763 64-Core Processor",
+		// ARM cpuinfo has no "model name", board model is used instead
+		"raspios-aarch64":  "Raspberry Pi 4 Model B Rev 1.4",
+		"armbian-aarch64":  "Orange Pi 3 LTS",
+		"alpine-container": "n/a",
+	}, (*System).getRawCpu)
+}
+
+func TestGetRawGpus(t *testing.T) {
+	forFixtures(t, map[string][]string{
+		"debian-12-x86_64": {
+			"Intel Alder Lake-P Integrated Graphics Controller",
+			"NVIDIA GeForce RTX 3050 Mobile",
+		},
+		"rocky-9-x86_64":   {"Device 1111"},
+		"raspios-aarch64":  {},
+		"armbian-aarch64":  {},
+		"alpine-container": {},
+	}, (*System).getRawGpus)
+}
+
+func TestGetRawScreenResolutions(t *testing.T) {
+	forFixtures(t, map[string][]string{
+		"debian-12-x86_64": {"1920x1080", "2560x1440"},
+		"rocky-9-x86_64":   {},
+		"raspios-aarch64":  {},
+		"armbian-aarch64":  {},
+		"alpine-container": {},
+	}, (*System).getRawScreenResolutions)
+}
+
+func TestGetRawPrettyName(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "Debian GNU/Linux 12 (bookworm)",
+		"rocky-9-x86_64":   "Rocky Linux 9.3 (Blue Onyx)",
+		"raspios-aarch64":  "Debian GNU/Linux 12 (bookworm)",
+		"armbian-aarch64":  "Armbian 23.8.1 bookworm",
+		"alpine-container": "Alpine Linux v3.19",
+	}, (*System).getRawPrettyName)
+}
+
+func TestGuessDistros(t *testing.T) {
+	forFixtures(t, map[string][]string{
+		"debian-12-x86_64": {"debian"},
+		"rocky-9-x86_64":   {"rocky", "rhel", "centos", "fedora"},
+		"raspios-aarch64":  {"raspbian", "debian"},
+		"armbian-aarch64":  {"debian"},
+		"alpine-container": {"alpine"},
+	}, (*System).guessDistros)
+}
+
+func TestGetRawBootId(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b",
+		"rocky-9-x86_64":   "",
+		"raspios-aarch64":  "",
+		"armbian-aarch64":  "",
+		"alpine-container": "",
+	}, (*System).getRawBootId)
+}
+
+func TestGetRawFQDN(t *testing.T) {
+	forFixtures(t, map[string]string{
+		"debian-12-x86_64": "deb-desktop.lan.example.org",
+		"rocky-9-x86_64":   "",
+		"raspios-aarch64":  "",
+		"armbian-aarch64":  "",
+		"alpine-container": "",
+	}, (*System).getRawFQDN)
+}
+
+func TestGetRawDisks(t *testing.T) {
+	forFixtures(t, map[string][]Disk{
+		// same mount given twice is reported once
+		"debian-12-x86_64": {
+			newDisk("/", 123456789*1024, (123456789+342623456)*1024),
+			newDisk("/mnt/My Data", 480150024*1024, (480150024+480150024)*1024),
+		},
+		"rocky-9-x86_64":  {},
+		"raspios-aarch64": {},
+		"armbian-aarch64": {},
+		// df fails on missing mount, but still reports others
+		"alpine-container": {newDisk("/", 9876543*1024, (9876543+48234567)*1024)},
+	}, func(s *System) []Disk {
+		return s.getRawDisks([]string{"/", "/data"})
+	})
+}
+
+func TestGetRawUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		env     map[string]string
+		current *user.User
+		euid    int
+		want    string
+	}{
+		{"USER", "debian-12-x86_64", map[string]string{"USER": "carol", "LOGNAME": "dave"}, nil, 1000, "carol"},
+		{"LOGNAME", "debian-12-x86_64", map[string]string{"LOGNAME": "dave"}, nil, 1000, "dave"},
+		{"current user", "debian-12-x86_64", nil, &user.User{Username: "erin"}, 1000, "erin"},
+		{"passwd", "debian-12-x86_64", nil, nil, 1000, "alice"},
+		{"passwd root", "alpine-container", nil, nil, 0, "root"},
+		{"unknown uid", "alpine-container", nil, nil, 1000, "n/a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := fixtureSystem(test.fixture)
+			s.Getenv = func(key string) string { return test.env[key] }
+			s.Geteuid = func() int { return test.euid }
+
+			if test.current != nil {
+				s.CurrentUser = func() (*user.User, error) { return test.current, nil }
+			}
+
+			if got := s.getRawUser(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetRawUserName(t *testing.T) {
+	tests := []struct {
+		name string
+		euid int
+		want string
+	}{
+		{"GECOS with fields", 1000, "Alice Liddell"},
+		{"empty GECOS", 1001, ""},
+		{"unknown uid", 4242, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := fixtureSystem("debian-12-x86_64")
+			s.Geteuid = func() int { return test.euid }
+
+			if got := s.getRawUserName(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetRawRealUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		uid, euid int
+		want      string
+	}{
+		{"sudo", map[string]string{"SUDO_USER": "bob", "DOAS_USER": "alice"}, 0, 0, "bob"},
+		{"doas", map[string]string{"DOAS_USER": "alice"}, 0, 0, "alice"},
+		{"setuid", nil, 1000, 0, "alice"},
+		{"effective user", nil, 1000, 1000, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := fixtureSystem("debian-12-x86_64")
+			s.Getenv = func(key string) string { return test.env[key] }
+			s.Getuid = func() int { return test.uid }
+			s.Geteuid = func() int { return test.euid }
+
+			if got := s.getRawRealUser(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetRawShell(t *testing.T) {
+	s := fixtureSystem("debian-12-x86_64")
+	s.Getenv = func(key string) string { return map[string]string{"SHELL": "/bin/zsh"}[key] }
+
+	if got := s.getRawShell(); got != "/bin/zsh" {
+		t.Errorf("got %q, want %q", got, "/bin/zsh")
+	}
+}
+
+func TestGetRawArchitecture(t *testing.T) {
+	s := fixtureSystem("raspios-aarch64")
+	s.Arch = "arm64"
+
+	if got := s.getRawArchitecture(); got != "arm64" {
+		t.Errorf("got %q, want %q", got, "arm64")
+	}
+}
+
+func TestGetRawLocalIp(t *testing.T) {
+	s := fixtureSystem("debian-12-x86_64")
+
+	if got := s.getRawLocalIp(); got != "n/a" {
+		t.Errorf("without network got %q, want %q", got, "n/a")
+	}
+
+	// UDP "connection" sends nothing, so loopback stands in for network
+	s.Dial = func(network, address string) (net.Conn, error) {
+		return net.Dial(network, "127.0.0.1:9")
+	}
+
+	if got := s.getRawLocalIp(); got != "127.0.0.1" {
+		t.Errorf("got %q, want %q", got, "127.0.0.1")
+	}
+}
+
+func TestGetRawOutboundIp(t *testing.T) {
+	s := fixtureSystem("debian-12-x86_64")
+
+	if got := s.getRawOutboundIp(); got != "n/a" {
+		t.Errorf("without network got %q, want %q", got, "n/a")
+	}
+
+	s.Client = &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("203.0.113.7")),
+			Request:    request,
+		}, nil
+	})}
+
+	if got := s.getRawOutboundIp(); got != "203.0.113.7" {
+		t.Errorf("got %q, want %q", got, "203.0.113.7")
+	}
+}
+
+func TestCollectJSONOmitsMissingModules(t *testing.T) {
+	tests := []struct {
+		fixture string
+		options map[string]string
+		keys    []string
+	}{
+		{"debian-12-x86_64", map[string]string{"memory": "true", "disk": "true"}, []string{"disks", "memory"}},
+		{"alpine-container", map[string]string{"os": "true", "swap": "true"}, []string{"os"}},
+	}
+
+	for _, test := range tests {
+		test.options["cache"] = "false"
+
+		collected := fixtureSystem(test.fixture).Collect(test.options)
+
+		raw, err := json.Marshal(collected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			t.Fatal(err)
+		}
+
+		keys := []string{}
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("%v: got keys %v, want %v in %s", test.fixture, keys, test.keys, raw)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package info
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// Size of pseudonym key in bytes
+const redactKeySize = 32
+
+// Pseudonym key of this install, read once per run
+var (
+	redactKeyOnce sync.Once
+	redactKey     []byte
+)
+
+// Regexes matching identifiers redacted in every info value
+var (
+	macAddressRegex = regexp.MustCompile(`(?i)\b[0-9a-f]{2}(?:[:-][0-9a-f]{2}){5}\b`)
+	ipAddressRegex  = regexp.MustCompile(`(?i)\b(?:\d{1,3}(?:\.\d{1,3}){3}|[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7})\b`)
+	serialRegex     = regexp.MustCompile(`(?i)\b(serial(?: ?number)?|s/n)(\s*[:=#]?\s*)([0-9a-z-]{4,})`)
+)
+
+// Redactor masks identifying info, like username, hostname, IP and MAC
+// addresses and serial numbers, for screenshots and bug reports
+type redactor struct {
+	// mode is "off", "mask" replacing values with "***", or "pseudonym"
+	// replacing them with pseudonyms, same for same values
+	mode string
+
+	// key is a secret pseudonyms are derived with
+	key []byte
+}
+
+// Returns redactor of redact option: false or off, true or mask, or
+// pseudonym
+func newRedactor(options map[string]string) (redactor, error) {
+	switch mode := options["redact"]; mode {
+	case "", "false", "off":
+		return redactor{mode: "off"}, nil
+
+	case "true", "mask":
+		return redactor{mode: "mask"}, nil
+
+	case "pseudonym":
+		return redactor{mode: mode, key: getRedactKey()}, nil
+
+	default:
+		return redactor{}, fmt.Errorf("%w: redact=%v", ErrInvalidOption, mode)
+	}
+}
+
+// Returns pseudonym key of this install, stored in cache directory, so
+// pseudonyms are same on every run, but can't be guessed from common values
+// by anyone without it. If key can't be stored, or there is no cache
+// directory, random key of this run is used
+func getRedactKey() []byte {
+	redactKeyOnce.Do(func() {
+		dir, err := getCacheDir()
+
+		var key []byte
+		if err == nil {
+			key, err = readRedactKey(filepath.Join(dir, "redact.key"))
+		}
+
+		if err != nil {
+			key = make([]byte, redactKeySize)
+			rand.Read(key)
+		}
+
+		redactKey = key
+	})
+
+	return redactKey
+}
+
+// Reads pseudonym key from path, or creates it with random key if it's
+// missing
+func readRedactKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == redactKeySize {
+		return key, nil
+	}
+
+	if err == nil {
+		return nil, fmt.Errorf("%v: invalid key size %v", path, len(key))
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key = make([]byte, redactKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		// other run created key first
+		return readRedactKey(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = file.Write(key)
+	if closeErr := file.Close(); err != nil || closeErr != nil {
+		os.Remove(path)
+		return nil, errors.Join(err, closeErr)
+	}
+
+	return key, nil
+}
+
+// Returns pseudonym of value of kind: user, host, ip, mac or serial.
+// Pseudonym is HMAC of value with key, so it's same on every run with same
+// key. IPs are replaced with documentation addresses
+func pseudonym(key []byte, kind, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kind + ":" + value))
+	sum := mac.Sum(nil)
+
+	switch kind {
+	case "ip":
+		if ip := net.ParseIP(value); ip != nil && ip.To4() == nil {
+			// 2001:db8::/32, rest of address is taken from sum
+			pseudo := append(net.IP{0x20, 0x01, 0x0d, 0xb8}, sum[:12]...)
+			return pseudo.String()
+		}
+
+		// one of 192.0.2.0/24, 198.51.100.0/24 and 203.0.113.0/24
+		networks := []string{"192.0.2", "198.51.100", "203.0.113"}
+		return fmt.Sprintf("%v.%v", networks[int(sum[0])%len(networks)], sum[1])
+
+	case "mac":
+		// locally administered unicast address
+		return fmt.Sprintf("02:%02x:%02x:%02x:%02x:%02x", sum[0], sum[1], sum[2], sum[3], sum[4])
+
+	case "serial":
+		return fmt.Sprintf("SN-%X", sum[:6])
+	}
+
+	return fmt.Sprintf("%v-%x", kind, sum[:4])
+}
+
+// Returns redacted value of kind
+func (r redactor) replace(kind, value string) string {
+	if r.mode == "off" || value == "" || value == "n/a" {
+		return value
+	}
+
+	if r.mode == "mask" {
+		return "***"
+	}
+
+	return pseudonym(r.key, kind, value)
+}
+
+// Returns text with username, full name and hostname of info, IP and MAC
+// addresses and serial numbers redacted
+func (r redactor) text(info Info, text string) string {
+	if r.mode == "off" {
+		return text
+	}
+
+	for _, identity := range []struct{ kind, value string }{
+		{"user", info.User},
+		{"user", info.RealUser},
+		{"host", info.Host},
+		{"name", info.UserName},
+	} {
+		// too short names match too much
+		if len(identity.value) < 2 {
+			continue
+		}
+
+		regex := regexp.MustCompile(`\b` + regexp.QuoteMeta(identity.value) + `\b`)
+		text = regex.ReplaceAllLiteralString(text, r.replace(identity.kind, identity.value))
+	}
+
+	text = macAddressRegex.ReplaceAllStringFunc(text, func(mac string) string {
+		return r.replace("mac", mac)
+	})
+
+	text = ipAddressRegex.ReplaceAllStringFunc(text, func(ip string) string {
+		if net.ParseIP(ip) == nil {
+			return ip
+		}
+
+		return r.replace("ip", ip)
+	})
+
+	return serialRegex.ReplaceAllStringFunc(text, func(serial string) string {
+		match := serialRegex.FindStringSubmatch(serial)
+		return match[1] + match[2] + r.replace("serial", match[3])
+	})
+}
+
+// Returns info with identifying values redacted
+func (r redactor) info(info Info) Info {
+	if r.mode == "off" {
+		return info
+	}
+
+	original := info
+
+	info.User = r.replace("user", original.User)
+	info.Host = r.replace("host", original.Host)
+	info.FQDN = r.replace("host", original.FQDN)
+	info.UserName = r.replace("name", original.UserName)
+	info.RealUser = r.replace("user", original.RealUser)
+	info.LocalIP = r.replace("ip", original.LocalIP)
+	info.RemoteIP = r.replace("ip", original.RemoteIP)
+
+	if original.OS != nil {
+		system := *original.OS
+		system.Name = r.text(original, system.Name)
+		info.OS = &system
+	}
+
+	info.Kernel = r.text(original, original.Kernel)
+	info.Shell = r.text(original, original.Shell)
+	info.CPU = r.text(original, original.CPU)
+
+	info.Resolutions = []string{}
+	for _, resolution := range original.Resolutions {
+		info.Resolutions = append(info.Resolutions, r.text(original, resolution))
+	}
+
+	info.GPUs = []string{}
+	for _, gpu := range original.GPUs {
+		info.GPUs = append(info.GPUs, r.text(original, gpu))
+	}
+
+	info.Disks = []Disk{}
+	for _, disk := range original.Disks {
+		disk.Mount = r.text(original, disk.Mount)
+		info.Disks = append(info.Disks, disk)
+	}
+
+	return info
+}
+
+// Returns info with identifying values redacted by redact option
+func redactInfo(options map[string]string, info Info) (Info, error) {
+	r, err := newRedactor(options)
+	if err != nil {
+		return info, err
+	}
+
+	return r.info(info), nil
+}
@@ -0,0 +1,92 @@
+package info
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadRedactKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "barkfetch", "redact.key")
+
+	key, err := readRedactKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key) != redactKeySize {
+		t.Errorf("key is %v bytes, want %v", len(key), redactKeySize)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := stat.Mode().Perm(); mode != 0o600 {
+		t.Errorf("key file mode is %v, want 0600", mode)
+	}
+
+	again, err := readRedactKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(again, key) {
+		t.Errorf("key changed on second read")
+	}
+
+	if err := os.WriteFile(path, []byte("short"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readRedactKey(path); err == nil {
+		t.Errorf("key of invalid size is accepted")
+	}
+}
+
+func TestPseudonym(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, redactKeySize)
+	other := bytes.Repeat([]byte{2}, redactKeySize)
+
+	for _, test := range []struct{ kind, value string }{
+		{"user", "alice"},
+		{"host", "workstation"},
+		{"ip", "10.0.0.5"},
+		{"ip", "fe80::1"},
+		{"mac", "aa:bb:cc:dd:ee:ff"},
+		{"serial", "ABC1234"},
+	} {
+		pseudo := pseudonym(key, test.kind, test.value)
+
+		if pseudo != pseudonym(key, test.kind, test.value) {
+			t.Errorf("%v %v: pseudonym isn't stable", test.kind, test.value)
+		}
+
+		if pseudo == pseudonym(other, test.kind, test.value) {
+			t.Errorf("%v %v: pseudonym doesn't depend on key", test.kind, test.value)
+		}
+	}
+
+	documentation := []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32"}
+
+	for _, value := range []string{"10.0.0.5", "172.16.1.1", "8.8.8.8", "fe80::1", "2a00::1"} {
+		ip := net.ParseIP(pseudonym(key, "ip", value))
+		if ip == nil {
+			t.Errorf("%v: pseudonym isn't IP", value)
+			continue
+		}
+
+		found := false
+		for _, network := range documentation {
+			_, ipnet, _ := net.ParseCIDR(network)
+			found = found || ipnet.Contains(ip)
+		}
+
+		if !found {
+			t.Errorf("%v: pseudonym %v isn't documentation address", value, ip)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package info
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Invalid option value error
+var ErrInvalidOption = errors.New("invalid option")
+
+// Renderer renders system info with it's own system, logos and color
+// depth. It holds no mutable state, so it's safe to render many fetches
+// with different options concurrently
+type Renderer struct {
+	// System is a system info is probed from
+	System *System
+
+	// Logos are directories logos are searched in, in order
+	Logos []LogoDir
+
+	// ColorDepth is a color depth of terminal colors are rendered for
+	ColorDepth int
+
+	// TerminalWidth is a width of terminal in columns, used to fit logo
+	// along with info, zero if it's unknown
+	TerminalWidth int
+
+	// CellWidth and CellHeight are a size of terminal cell in pixels, used
+	// to scale image logos, zero if it's unknown
+	CellWidth, CellHeight int
+
+	// ImageBackend is a protocol image logos are drawn with: kitty, iterm2
+	// or sixel, empty if terminal supports none
+	ImageBackend string
+
+	// Debug is a writer for debug messages, like why logo was chosen,
+	// nil disables them
+	Debug io.Writer
+}
+
+// NewRenderer returns Renderer probing current machine, with default logo
+// directories and color depth, size and image backend of current terminal
+func NewRenderer() *Renderer {
+	cellWidth, cellHeight := DetectCellSize()
+
+	return &Renderer{
+		System:        NewSystem(),
+		Logos:         DefaultLogoDirs(),
+		ColorDepth:    DetectColorDepth(),
+		TerminalWidth: DetectTerminalWidth(),
+		CellWidth:     cellWidth,
+		CellHeight:    cellHeight,
+		ImageBackend:  DetectImageBackend(),
+	}
+}
+
+// DetectTerminalWidth returns width of terminal on standard output, or
+// COLUMNS variable if it's not a terminal, or zero if both are unknown
+func DetectTerminalWidth() int {
+	size, err := getWinsize(os.Stdout.Fd())
+	if err == nil && size.Col > 0 {
+		return int(size.Col)
+	}
+
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil {
+		return 0
+	}
+
+	return columns
+}
+
+// DetectCellSize returns size of terminal cell on standard output in
+// pixels, or zeros if terminal doesn't report it
+func DetectCellSize() (width, height int) {
+	size, err := getWinsize(os.Stdout.Fd())
+	if err != nil || size.Col == 0 || size.Row == 0 {
+		return 0, 0
+	}
+
+	return int(size.Xpixel / size.Col), int(size.Ypixel / size.Row)
+}
+
+// GetInfoString renders info with NewRenderer, see Renderer.Render
+func GetInfoString(options map[string]string) (string, error) {
+	return NewRenderer().Render(options)
+}
+
+// GetTemplateString renders template with NewRenderer, see
+// Renderer.RenderTemplate
+func GetTemplateString(options map[string]string, text string) (string, error) {
+	return NewRenderer().RenderTemplate(options, text)
+}
+
+// Writes debug message if debug is enabled
+func (r *Renderer) debugf(format string, args ...any) {
+	if r.Debug == nil {
+		return
+	}
+
+	fmt.Fprintf(r.Debug, "debug: "+format+"\n", args...)
+}
+
+// Returns logo by name or path, guessing it by system distro if name is
+// "auto", falls back to default logo if name is not found. Unreadable path
+// is an error, as it's likely a typo. Returns name of logo actually read
+// and directory it was read from
+func (r *Renderer) getLogo(name string) (string, LogoDir, Logo, error) {
+	if name == "auto" {
+		var reason string
+
+		name, reason = chooseLogo(r.Logos, r.System.guessDistros())
+		r.debugf("logo: chose %v (%v)", name, reason)
+	}
+
+	var text string
+	var dir LogoDir
+	var found bool
+
+	if isLogoPath(name) {
+		bytes, err := os.ReadFile(name)
+		if err != nil {
+			return name, dir, Logo{}, fmt.Errorf("logo: %w", err)
+		}
+
+		text, dir, found = string(bytes), LogoDir{Path: "file"}, true
+	} else {
+		text, dir, found = findLogo(r.Logos, name)
+	}
+
+	if !found {
+		r.debugf("logo: no logo %v, chose default", name)
+
+		name = "default"
+		text, dir, found = findLogo(r.Logos, name)
+	}
+
+	if !found {
+		text, dir = _default, LogoDir{Path: "embedded"}
+	}
+
+	r.debugf("logo: read %v from %v", name, dir.Path)
+
+	logo, err := ParseLogo(text)
+	if err != nil {
+		return name, dir, logo, fmt.Errorf("logo %v: %w", name, err)
+	}
+
+	return name, dir, logo, nil
+}
+
+// Returns small variant name of logo name or path, e.g. "arch_small"
+func smallLogoName(name string) string {
+	if isLogoPath(name) {
+		return strings.TrimSuffix(name, ".txt") + "_small.txt"
+	}
+
+	return name + "_small"
+}
+
+// Fits logo to size: "large" keeps it, "small" replaces it with small
+// variant from same directory if there is one, "none" drops it. "auto", the
+// default, keeps logo if it fits terminal, then tries small variant, then
+// drops logo. Dropped logo is returned as empty Logo
+func (r *Renderer) fitLogo(name string, dir LogoDir, logo Logo, size string, fits func(Logo) bool) (Logo, error) {
+	switch size {
+	case "large":
+		return logo, nil
+
+	case "none":
+		r.debugf("logo: dropped, logo_size is none")
+		return Logo{}, nil
+
+	case "small", "auto", "":
+		if size != "small" && fits(logo) {
+			return logo, nil
+		}
+
+	default:
+		return Logo{}, fmt.Errorf("%w: logo_size=%v", ErrInvalidOption, size)
+	}
+
+	// small variant of other directory may not match logo, e.g. user's
+	// arch.txt with embedded arch_small.txt
+	text, _, found := findLogo([]LogoDir{dir}, smallLogoName(name))
+	if found {
+		small, err := ParseLogo(text)
+		if err != nil {
+			return Logo{}, fmt.Errorf("logo %v: %w", smallLogoName(name), err)
+		}
+
+		if size == "small" || fits(small) {
+			r.debugf("logo: read %v from %v", smallLogoName(name), dir.Path)
+			return small, nil
+		}
+	}
+
+	if size == "small" {
+		return logo, nil
+	}
+
+	r.debugf("logo: dropped, terminal is %v columns wide", r.TerminalWidth)
+	return Logo{}, nil
+}
@@ -0,0 +1,404 @@
+package info
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os/user"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Not recorded in snapshot error, returned by replayed System for files,
+// commands and network connections missing in snapshot
+var ErrNotRecorded = errors.New("not recorded in snapshot")
+
+// Snapshot is a recording of every file, command output and environment
+// variable probes read. Entries are named like in snapshot tar archive:
+// "files/proc/meminfo", "commands/lspci%20-mm" (command line is escaped as
+// URL path), "env/SHELL", "arch", "uid", "euid" and "user", which is
+// current username and full name on two lines. Only lines of looked up
+// users are recorded in "files/etc/passwd". Error of failed command is
+// recorded along with it's output as "commands/<command line>.err".
+// Network probes are never recorded, so replayed local and remote IP are
+// always "n/a"
+type Snapshot struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// NewSnapshot returns empty Snapshot
+func NewSnapshot() *Snapshot {
+	return &Snapshot{entries: make(map[string][]byte)}
+}
+
+// Returns snapshot entry name of command
+func commandEntry(name string, args ...string) string {
+	return "commands/" + url.PathEscape(strings.Join(append([]string{name}, args...), " "))
+}
+
+// Stores entry
+func (snapshot *Snapshot) set(name string, contents []byte) {
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+
+	snapshot.entries[name] = contents
+}
+
+// Returns entry, false if it's missing
+func (snapshot *Snapshot) get(name string) ([]byte, bool) {
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+
+	contents, exists := snapshot.entries[name]
+	return contents, exists
+}
+
+// WriteTar writes snapshot as tar archive
+func (snapshot *Snapshot) WriteTar(w io.Writer) error {
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+
+	names := make([]string, 0, len(snapshot.entries))
+	for name := range snapshot.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := tar.NewWriter(w)
+
+	for _, name := range names {
+		contents := snapshot.entries[name]
+
+		err := archive.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(contents)),
+			ModTime: time.Unix(0, 0),
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := archive.Write(contents); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// ReadSnapshot reads snapshot from tar archive
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	snapshot := NewSnapshot()
+	archive := tar.NewReader(r)
+
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		contents, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: %w", err)
+		}
+
+		snapshot.entries[header.Name] = contents
+	}
+
+	return snapshot, nil
+}
+
+// Record returns System probing s, which records everything probes read
+// into snapshot
+func (s *System) Record(snapshot *Snapshot) *System {
+	snapshot.set("arch", []byte(s.Arch))
+
+	return &System{
+		Root:   &recordingFS{root: s.Root, snapshot: snapshot},
+		Runner: &recordingRunner{runner: s.Runner, snapshot: snapshot},
+		Getenv: func(key string) string {
+			value := s.Getenv(key)
+			snapshot.set("env/"+key, []byte(value))
+
+			return value
+		},
+		Getuid: func() int {
+			uid := s.Getuid()
+			snapshot.set("uid", []byte(strconv.Itoa(uid)))
+
+			return uid
+		},
+		Geteuid: func() int {
+			euid := s.Geteuid()
+			snapshot.set("euid", []byte(strconv.Itoa(euid)))
+
+			return euid
+		},
+		CurrentUser: func() (*user.User, error) {
+			current, err := s.CurrentUser()
+			if err == nil {
+				snapshot.set("user", []byte(current.Username+"\n"+current.Name))
+			}
+
+			return current, err
+		},
+		Dial:   s.Dial,
+		Client: s.Client,
+		Arch:   s.Arch,
+	}
+}
+
+// System returns System replaying snapshot
+func (snapshot *Snapshot) System() *System {
+	arch, _ := snapshot.get("arch")
+
+	return &System{
+		Root:   &snapshotFS{snapshot: snapshot},
+		Runner: &snapshotRunner{snapshot: snapshot},
+		Getenv: func(key string) string {
+			value, _ := snapshot.get("env/" + key)
+			return string(value)
+		},
+		Getuid:  func() int { return snapshot.id("uid") },
+		Geteuid: func() int { return snapshot.id("euid") },
+		CurrentUser: func() (*user.User, error) {
+			value, exists := snapshot.get("user")
+			if !exists {
+				return nil, ErrNotRecorded
+			}
+
+			username, name, _ := strings.Cut(string(value), "\n")
+			return &user.User{Username: username, Name: name}, nil
+		},
+		Dial: func(network, address string) (net.Conn, error) {
+			return nil, ErrNotRecorded
+		},
+		Client: &http.Client{Transport: notRecordedTransport{}},
+		Arch:   string(arch),
+	}
+}
+
+// Redact redacts identifying values in every entry by redact option, like
+// info is redacted: username, full name and hostname read from snapshot,
+// IP and MAC addresses and serial numbers. Replayed snapshot shows masked
+// values or same pseudonyms
+func (snapshot *Snapshot) Redact(options map[string]string) error {
+	r, err := newRedactor(options)
+	if err != nil {
+		return err
+	}
+
+	if r.mode == "off" {
+		return nil
+	}
+
+	s := snapshot.System()
+	identities := Info{
+		User:     s.getRawUser(),
+		Host:     s.getRawHostname(),
+		UserName: s.getRawUserName(),
+		RealUser: s.getRawRealUser(),
+	}
+
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+
+	for name, contents := range snapshot.entries {
+		snapshot.entries[name] = []byte(r.text(identities, string(contents)))
+	}
+
+	return nil
+}
+
+// Returns recorded user ID entry, or -1 if it's missing
+func (snapshot *Snapshot) id(name string) int {
+	value, _ := snapshot.get(name)
+
+	id, err := strconv.Atoi(string(value))
+	if err != nil {
+		return -1
+	}
+
+	return id
+}
+
+// recordingFS is a file system recording files read from root
+type recordingFS struct {
+	root     fs.FS
+	snapshot *Snapshot
+}
+
+// Open opens file from root and records it's contents
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	contents, err := r.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotFile(name, contents), nil
+}
+
+// ReadFile reads file from root and records it's contents
+func (r *recordingFS) ReadFile(name string) ([]byte, error) {
+	contents, err := fs.ReadFile(r.root, name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.snapshot.set("files/"+name, contents)
+
+	return contents, nil
+}
+
+// lookupPasswd looks user up in /etc/passwd of root, recording only it's
+// line, so snapshot doesn't contain other accounts
+func (r *recordingFS) lookupPasswd(uid int) (string, bool) {
+	raw, err := fs.ReadFile(r.root, "etc/passwd")
+	if err != nil {
+		return "", false
+	}
+
+	line, found := findPasswdLine(string(raw), uid)
+	if !found {
+		return "", false
+	}
+
+	r.snapshot.mu.Lock()
+	defer r.snapshot.mu.Unlock()
+
+	// real and effective users may be looked up, both are kept
+	recorded := string(r.snapshot.entries["files/etc/passwd"])
+	if _, exists := findPasswdLine(recorded, uid); !exists {
+		r.snapshot.entries["files/etc/passwd"] = []byte(recorded + line + "\n")
+	}
+
+	return line, true
+}
+
+// recordingRunner is a Runner recording commands output
+type recordingRunner struct {
+	runner   Runner
+	snapshot *Snapshot
+}
+
+// Output runs command and records it's output, and error if it failed, as
+// probes may use output of failed commands
+func (r *recordingRunner) Output(name string, args ...string) ([]byte, error) {
+	out, err := r.runner.Output(name, args...)
+
+	r.snapshot.set(commandEntry(name, args...), out)
+
+	if err != nil {
+		r.snapshot.set(commandEntry(name, args...)+".err", []byte(err.Error()))
+	}
+
+	return out, err
+}
+
+// snapshotFS is a file system of files recorded in snapshot
+type snapshotFS struct {
+	snapshot *Snapshot
+}
+
+// Open opens recorded file
+func (s *snapshotFS) Open(name string) (fs.File, error) {
+	contents, err := s.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotFile(name, contents), nil
+}
+
+// ReadFile returns recorded file contents
+func (s *snapshotFS) ReadFile(name string) ([]byte, error) {
+	contents, exists := s.snapshot.get("files/" + name)
+	if !exists {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return contents, nil
+}
+
+// snapshotRunner is a Runner returning recorded commands output
+type snapshotRunner struct {
+	snapshot *Snapshot
+}
+
+// Output returns recorded command output, and recorded error if command
+// failed
+func (s *snapshotRunner) Output(name string, args ...string) ([]byte, error) {
+	out, exists := s.snapshot.get(commandEntry(name, args...))
+	if !exists {
+		return nil, fmt.Errorf("%v: %w", name, ErrNotRecorded)
+	}
+
+	if message, failed := s.snapshot.get(commandEntry(name, args...) + ".err"); failed {
+		return out, errors.New(string(message))
+	}
+
+	return out, nil
+}
+
+// notRecordedTransport is a HTTP transport failing every request
+type notRecordedTransport struct{}
+
+// RoundTrip returns ErrNotRecorded
+func (notRecordedTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, ErrNotRecorded
+}
+
+// snapshotFile is an in-memory read-only file
+type snapshotFile struct {
+	*bytes.Reader
+	name string
+}
+
+// Returns in-memory file with contents
+func newSnapshotFile(name string, contents []byte) *snapshotFile {
+	return &snapshotFile{
+		Reader: bytes.NewReader(contents),
+		name:   path.Base(name),
+	}
+}
+
+// Stat returns file info of file itself
+func (f *snapshotFile) Stat() (fs.FileInfo, error) { return f, nil }
+
+// Close does nothing
+func (f *snapshotFile) Close() error { return nil }
+
+// Name returns base name of file
+func (f *snapshotFile) Name() string { return f.name }
+
+// Mode returns read-only regular file mode
+func (f *snapshotFile) Mode() fs.FileMode { return 0o444 }
+
+// ModTime returns zero time
+func (f *snapshotFile) ModTime() time.Time { return time.Time{} }
+
+// IsDir returns false
+func (f *snapshotFile) IsDir() bool { return false }
+
+// Sys returns nil
+func (f *snapshotFile) Sys() any { return nil }
@@ -0,0 +1,42 @@
+//go:build linux
+
+package info
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshotRedact(t *testing.T) {
+	options := map[string]string{
+		"cache":         "false",
+		"userline":      "true",
+		"userline_name": "true",
+		"os":            "true",
+		"cpu":           "true",
+		"redact":        "mask",
+	}
+
+	snapshot := NewSnapshot()
+	fixtureSystem("debian-12-x86_64").Record(snapshot).Collect(options)
+
+	if err := snapshot.Redact(options); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range snapshot.entries {
+		for _, identity := range []string{"alice", "Alice Liddell", "deb-desktop"} {
+			if bytes.Contains(contents, []byte(identity)) {
+				t.Errorf("%v contains %q:\n%s", name, identity, contents)
+			}
+		}
+	}
+
+	options["redact"] = "false"
+	replayed := snapshot.System().Collect(options)
+
+	if replayed.User != "***" || replayed.Host != "***" || replayed.UserName != "***" {
+		t.Errorf("replayed user %q, host %q, name %q, want them masked",
+			replayed.User, replayed.Host, replayed.UserName)
+	}
+}
@@ -0,0 +1,99 @@
+package info
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotReplay(t *testing.T) {
+	options := map[string]string{
+		"cache":       "false",
+		"userline":    "true",
+		"os":          "true",
+		"kernel":      "true",
+		"uptime":      "true",
+		"resolution":  "true",
+		"cpu":         "true",
+		"gpu":         "true",
+		"memory":      "true",
+		"swap":        "true",
+		"disk":        "true",
+		"disk_mounts": "/,/data",
+		"battery":     "true",
+	}
+
+	for _, fixture := range []string{"debian-12-x86_64", "alpine-container"} {
+		t.Run(fixture, func(t *testing.T) {
+			snapshot := NewSnapshot()
+			recorded := fixtureSystem(fixture).Record(snapshot).Collect(options)
+
+			var archive bytes.Buffer
+			if err := snapshot.WriteTar(&archive); err != nil {
+				t.Fatal(err)
+			}
+
+			read, err := ReadSnapshot(&archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			replayed := read.System().Collect(options)
+			if !reflect.DeepEqual(replayed, recorded) {
+				t.Errorf("replayed %#v, recorded %#v", replayed, recorded)
+			}
+		})
+	}
+}
+
+func TestSnapshotFailedCommand(t *testing.T) {
+	snapshot := NewSnapshot()
+	fixtureSystem("alpine-container").Record(snapshot).getRawDisks([]string{"/", "/data"})
+
+	entry := commandEntry("df", "-Pk", "/", "/data")
+	if entry != "commands/df%20-Pk%20%2F%20%2Fdata" {
+		t.Errorf("entry is %q", entry)
+	}
+
+	if _, exists := snapshot.get(entry); !exists {
+		t.Errorf("output of failed command is not recorded")
+	}
+
+	if message, _ := snapshot.get(entry + ".err"); string(message) != "exit status 1" {
+		t.Errorf("error of failed command is %q, want %q", message, "exit status 1")
+	}
+
+	out, err := snapshot.System().Runner.Output("df", "-Pk", "/", "/data")
+	if err == nil || len(out) == 0 {
+		t.Errorf("replayed failed command returned %q, %v", out, err)
+	}
+}
+
+func TestSnapshotPasswd(t *testing.T) {
+	system := fixtureSystem("debian-12-x86_64")
+	system.Getuid = func() int { return 1001 }
+
+	snapshot := NewSnapshot()
+	recording := system.Record(snapshot)
+
+	if name := recording.getRawUserName(); name != "Alice Liddell" {
+		t.Errorf("name is %q", name)
+	}
+
+	if user := recording.getRawRealUser(); user != "bob" {
+		t.Errorf("real user is %q", user)
+	}
+
+	recording.getRawUser()
+
+	passwd, _ := snapshot.get("files/etc/passwd")
+	want := "alice:x:1000:1000:Alice Liddell,,,:/home/alice:/bin/zsh\nbob:x:1001:1001::/home/bob:/bin/bash\n"
+	if string(passwd) != want {
+		t.Errorf("recorded passwd is %q, want %q", passwd, want)
+	}
+
+	replayed := snapshot.System()
+	if name := replayed.getRawUserName(); name != "Alice Liddell" {
+		t.Errorf("replayed name is %q", name)
+	}
+}
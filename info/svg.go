@@ -0,0 +1,98 @@
+package info
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Sizes of SVG output in pixels, cell width is usual advance of monospace
+// fonts, 0.6 of font size
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4
+	svgLineHeight = 18
+	svgPadding    = 16
+)
+
+// Fonts of SVG output, first one installed is used
+const svgFonts = `ui-monospace, 'SF Mono', Menlo, Consolas, 'DejaVu Sans Mono', monospace`
+
+// ConvertSVG converts lines with ANSI colors, like lines of RenderLines,
+// into SVG image of terminal with monospace text. Colors are drawn with
+// palette from "palette.<color>" options
+func ConvertSVG(lines []string, options map[string]string) (string, error) {
+	p, err := parsePalette(options)
+	if err != nil {
+		return "", err
+	}
+
+	width := float64(maxWidth(lines))*svgCellWidth + 2*svgPadding
+	height := len(lines)*svgLineHeight + 2*svgPadding
+
+	var backgrounds, texts strings.Builder
+
+	for i, runs := range styledLines(lines, p) {
+		top := svgPadding + i*svgLineHeight
+		column := 0
+
+		for _, run := range runs {
+			x := svgPadding + float64(column)*svgCellWidth
+			runWidth := displayWidth(run.text)
+			column += runWidth
+
+			if run.style.background != "" {
+				fmt.Fprintf(&backgrounds, `<rect x="%.1f" y="%v" width="%.1f" height="%v" fill="%v"/>`+"\n",
+					x, top, float64(runWidth)*svgCellWidth, svgLineHeight, run.style.background)
+			}
+
+			if strings.TrimSpace(run.text) == "" && !run.style.underline {
+				continue
+			}
+
+			attributes := ""
+
+			if run.style.foreground != "" {
+				attributes += fmt.Sprintf(` fill="%v"`, run.style.foreground)
+			}
+
+			if run.style.bold {
+				attributes += ` font-weight="bold"`
+			}
+
+			if run.style.underline {
+				attributes += ` text-decoration="underline"`
+			}
+
+			// text is stretched to it's cells, so fonts with other advance
+			// keep columns aligned
+			fmt.Fprintf(&texts, `<text x="%.1f" y="%v" textLength="%.1f" lengthAdjust="spacingAndGlyphs"%v>%v</text>`+"\n",
+				x, top+svgFontSize, float64(runWidth)*svgCellWidth, attributes, html.EscapeString(run.text))
+		}
+	}
+
+	var output strings.Builder
+
+	fmt.Fprintf(&output, `<svg xmlns="http://www.w3.org/2000/svg" width="%.1f" height="%v" viewBox="0 0 %.1f %v">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&output, `<rect width="100%%" height="100%%" rx="6" fill="%v"/>`+"\n", p.background)
+	output.WriteString(backgrounds.String())
+	fmt.Fprintf(&output, `<g font-family="%v" font-size="%v" fill="%v" xml:space="preserve">`+"\n",
+		svgFonts, svgFontSize, p.foreground)
+	output.WriteString(texts.String())
+	output.WriteString("</g>\n</svg>\n")
+
+	return output.String(), nil
+}
+
+// RenderSVG returns processed info as SVG image, with colors rendered in
+// truecolor, or template file from "template" option rendered same way.
+// Image logos are not drawn
+func (r *Renderer) RenderSVG(options map[string]string) (string, error) {
+	lines, err := r.truecolorLines(options)
+	if err != nil {
+		return "", err
+	}
+
+	return ConvertSVG(lines, options)
+}
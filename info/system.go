@@ -0,0 +1,81 @@
+package info
+
+import (
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strings"
+)
+
+// Runner runs external commands for probes
+type Runner interface {
+	// Output runs command and returns it's standard output
+	Output(name string, args ...string) ([]byte, error)
+}
+
+// ExecRunner is a Runner running commands with os/exec
+type ExecRunner struct{}
+
+// Output runs command with os/exec and returns it's standard output
+func (ExecRunner) Output(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// System is an environment probes gather info from. Every file, command,
+// environment variable and network connection probes use goes through it,
+// so it can be replaced to probe recorded or fake system
+type System struct {
+	// Root is a file system root, probes read "/proc/meminfo" from it as
+	// "proc/meminfo"
+	Root fs.FS
+
+	// Runner runs external commands, like "lspci" or "xrandr"
+	Runner Runner
+
+	// Getenv returns environment variable by key
+	Getenv func(key string) string
+
+	// Getuid and Geteuid return real and effective user ID
+	Getuid, Geteuid func() int
+
+	// CurrentUser returns current user, used if USER variable is empty
+	CurrentUser func() (*user.User, error)
+
+	// Dial connects to address, used to get local IP
+	Dial func(network, address string) (net.Conn, error)
+
+	// Client is a HTTP client used to get outbound IP
+	Client *http.Client
+
+	// Arch is an OS architecture
+	Arch string
+}
+
+// NewSystem returns System probing current machine
+func NewSystem() *System {
+	return &System{
+		Root:        os.DirFS("/"),
+		Runner:      ExecRunner{},
+		Getenv:      os.Getenv,
+		Getuid:      os.Getuid,
+		Geteuid:     os.Geteuid,
+		CurrentUser: user.Current,
+		Dial:        net.Dial,
+		Client:      http.DefaultClient,
+		Arch:        runtime.GOARCH,
+	}
+}
+
+// Reads file by absolute path from root
+func (s *System) readFile(path string) ([]byte, error) {
+	return fs.ReadFile(s.Root, strings.TrimPrefix(path, "/"))
+}
+
+// Runs command and returns it's output
+func (s *System) output(name string, args ...string) ([]byte, error) {
+	return s.Runner.Output(name, args...)
+}
@@ -0,0 +1,68 @@
+package info
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// fakeRunner is a Runner returning command output from "commands/<name>"
+// file of fixture, and error from "commands/<name>.err" if it exists.
+// Commands without output file are not installed
+type fakeRunner struct {
+	root fs.FS
+}
+
+// Output returns recorded output of command
+func (r fakeRunner) Output(name string, args ...string) ([]byte, error) {
+	out, err := fs.ReadFile(r.root, "commands/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", name, exec.ErrNotFound)
+	}
+
+	message, err := fs.ReadFile(r.root, "commands/"+name+".err")
+	if err == nil {
+		return out, errors.New(strings.TrimSpace(string(message)))
+	}
+
+	return out, nil
+}
+
+// roundTripperFunc is a HTTP transport calling function
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls function
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// Returns System probing fixture from testdata, running as user 1000 with
+// empty environment and no network
+func fixtureSystem(name string) *System {
+	root := os.DirFS(filepath.Join("testdata", name))
+
+	return &System{
+		Root:    root,
+		Runner:  fakeRunner{root: root},
+		Getenv:  func(string) string { return "" },
+		Getuid:  func() int { return 1000 },
+		Geteuid: func() int { return 1000 },
+		CurrentUser: func() (*user.User, error) {
+			return nil, user.UnknownUserIdError(1000)
+		},
+		Dial: func(network, address string) (net.Conn, error) {
+			return nil, errors.New("network is disabled in tests")
+		},
+		Client: &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("network is disabled in tests")
+		})},
+		Arch: "amd64",
+	}
+}
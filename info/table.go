@@ -0,0 +1,134 @@
+package info
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Escapes Markdown characters in table cells
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, `|`, `\|`, `*`, `\*`, `_`, `\_`, "`", "\\`", `<`, `\<`, `>`, `\>`,
+)
+
+// Field is a label and plain value of module info line
+type Field struct {
+	// Module is a module name, like "cpu"
+	Module string
+
+	// Label and Value are label and formatted value of info line
+	Label, Value string
+}
+
+// Fields returns plain title and underline, and fields of modules enabled
+// in options, one for every info line. Title and underline are empty if
+// userline and userunderline are disabled
+func (r *Renderer) Fields(options map[string]string) (title, underline string, fields []Field, err error) {
+	fields = []Field{}
+
+	info, err := r.Collect(options)
+	if err != nil {
+		return "", "", fields, err
+	}
+
+	theme, err := loadTheme(options, r.ColorDepth)
+	if err != nil {
+		return "", "", fields, err
+	}
+
+	for _, module := range possibleOptions {
+		if !isEnabled(options, module) {
+			continue
+		}
+
+		switch module {
+		case "userline":
+			title, err = userLine(options, info, theme)
+			if err != nil {
+				return "", "", fields, err
+			}
+
+			title = StripEscapes(title)
+
+		case "userunderline":
+			line, err := userLine(options, info, theme)
+			if err != nil {
+				return "", "", fields, err
+			}
+
+			underline = StripEscapes(underlineLine(line, theme))
+		}
+
+		if _, labeled := defaultLabels[module]; !labeled {
+			continue
+		}
+
+		values, err := getModuleLineValues(options, info, module)
+		if err != nil {
+			return "", "", fields, err
+		}
+
+		for _, value := range values {
+			fields = append(fields, Field{
+				Module: module,
+				Label:  getLabel(options, module),
+				Value:  StripEscapes(value),
+			})
+		}
+	}
+
+	return title, underline, fields, nil
+}
+
+// RenderMarkdown returns info of modules enabled in options as two-column
+// Markdown table of labels and values, with bold title before it
+func (r *Renderer) RenderMarkdown(options map[string]string) (string, error) {
+	title, _, fields, err := r.Fields(options)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+
+	if title != "" {
+		fmt.Fprintf(&output, "**%v**\n\n", markdownReplacer.Replace(title))
+	}
+
+	output.WriteString("| Label | Value |\n| --- | --- |\n")
+
+	for _, field := range fields {
+		fmt.Fprintf(&output, "| %v | %v |\n",
+			markdownReplacer.Replace(field.Label), markdownReplacer.Replace(field.Value))
+	}
+
+	return output.String(), nil
+}
+
+// RenderTable returns info of modules enabled in options as plain text
+// labels and values, aligned by longest label, without logo and colors
+func (r *Renderer) RenderTable(options map[string]string) (string, error) {
+	title, underline, fields, err := r.Fields(options)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+
+	for _, line := range []string{title, underline} {
+		if line != "" {
+			output.WriteString(line + "\n")
+		}
+	}
+
+	width := 0
+	for _, field := range fields {
+		if labelWidth := displayWidth(field.Label); labelWidth > width {
+			width = labelWidth
+		}
+	}
+
+	for _, field := range fields {
+		output.WriteString(pad(width+2, field.Label+":") + field.Value + "\n")
+	}
+
+	return output.String(), nil
+}
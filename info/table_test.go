@@ -0,0 +1,31 @@
+package info
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	system := fixtureSystem("debian-12-x86_64")
+	system.Getenv = func(key string) string {
+		if key == "SHELL" {
+			return "/bin/my_sh"
+		}
+
+		return ""
+	}
+
+	renderer := &Renderer{System: system}
+	options := map[string]string{
+		"cache":       "false",
+		"shell":       "true",
+		"label.shell": "Login | Shell",
+	}
+
+	output, err := renderer.RenderMarkdown(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "| Label | Value |\n| --- | --- |\n| Login \\| Shell | /bin/my\\_sh |\n"
+	if output != want {
+		t.Errorf("got %q, want %q", output, want)
+	}
+}
@@ -0,0 +1,204 @@
+package info
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Regex matching ${name} color directives, like ${c4}, ${#5e81ac} or
+// ${bg#5e81ac}
+var colorDirectiveRegex = regexp.MustCompile(`\$\{((?:bg)?#?\w+)\}`)
+
+// TemplateData is a data passed to whole-output templates
+type TemplateData struct {
+	Info
+
+	// Logo is a chosen logo, empty if logo is disabled
+	Logo Logo
+}
+
+// Expands ${name} color directives with theme, unknown names are left as is
+func expandColorDirectives(theme Theme, text string) string {
+	return colorDirectiveRegex.ReplaceAllStringFunc(text, func(directive string) string {
+		name := colorDirectiveRegex.FindStringSubmatch(directive)[1]
+
+		color, exists := theme.lookup(name)
+		if !exists {
+			return directive
+		}
+
+		return color
+	})
+}
+
+// Returns logo lines with color directives expanded, every line starts
+// with color active at it's beginning and ends with reset
+func logoLines(theme Theme, logo Logo) []string {
+	if logo.Logo == "" {
+		return []string{}
+	}
+
+	lines := []string{}
+	color := ""
+
+	for _, line := range strings.Split(logo.Logo, "\n") {
+		lines = append(lines, expandColorDirectives(theme, color+line+"${creset}"))
+
+		directives := colorDirectiveRegex.FindAllString(line, -1)
+		if len(directives) > 0 {
+			color = directives[len(directives)-1]
+		}
+	}
+
+	return lines
+}
+
+// Returns text repeated count times, or empty string if count is negative
+func repeat(text string, count int) string {
+	if count <= 0 {
+		return ""
+	}
+
+	return strings.Repeat(text, count)
+}
+
+// Returns list element by index, or empty string if it's out of range
+func at(list []string, index int) string {
+	if index < 0 || index >= len(list) {
+		return ""
+	}
+
+	return list[index]
+}
+
+// Returns sequence of integers from 0 to count-1
+func seq(count int) []int {
+	sequence := []int{}
+	for i := 0; i < count; i++ {
+		sequence = append(sequence, i)
+	}
+
+	return sequence
+}
+
+// Returns text padded with spaces to display width
+func pad(width int, text string) string {
+	padding := width - displayWidth(text)
+	if padding <= 0 {
+		return text
+	}
+
+	return text + strings.Repeat(" ", padding)
+}
+
+// RenderTemplate renders info of modules enabled in options with Go
+// text/template, ${name} color directives in template are expanded
+func (r *Renderer) RenderTemplate(options map[string]string, text string) (string, error) {
+	info, err := r.Collect(options)
+	if err != nil {
+		return "", err
+	}
+
+	data := TemplateData{Info: info}
+
+	theme, err := loadTheme(options, r.ColorDepth)
+	if err != nil {
+		return "", err
+	}
+
+	if isEnabled(options, "logo") {
+		var name string
+		var dir LogoDir
+
+		name, dir, data.Logo, err = r.getLogo(options["logo"])
+		if err != nil {
+			return "", err
+		}
+
+		if theme.Accent == "" {
+			theme.Accent = data.Logo.AccentColor
+		}
+
+		// layout of template is unknown, so "auto" keeps logo as is
+		size := options["logo_size"]
+		if size == "auto" || size == "" {
+			size = "large"
+		}
+
+		data.Logo, err = r.fitLogo(name, dir, data.Logo, size, func(Logo) bool {
+			return true
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	funcs := template.FuncMap{
+		"color": theme.expand,
+		"theme": func(part string) string {
+			var color string
+
+			switch part {
+			case "label":
+				color = theme.Label
+			case "separator":
+				color = theme.Separator
+			case "value":
+				color = theme.Value
+			case "title":
+				color = theme.Title
+			case "underline":
+				color = theme.Underline
+			}
+
+			return theme.expand(color)
+		},
+		"reset": func() string { return theme.expand("creset") },
+		"label": func(module string) string { return getLabel(options, module) },
+		"value": func(module string) (string, error) {
+			values, err := getModuleLineValues(options, data.Info, module)
+			return strings.Join(values, ", "), err
+		},
+		"values": func(module string) ([]string, error) {
+			return getModuleLineValues(options, data.Info, module)
+		},
+		"modules": func() []string {
+			modules := []string{}
+			for _, module := range possibleOptions {
+				_, labeled := defaultLabels[module]
+				if labeled && isEnabled(options, module) {
+					modules = append(modules, module)
+				}
+			}
+
+			return modules
+		},
+		"logo":   func() []string { return logoLines(theme, data.Logo) },
+		"at":     at,
+		"seq":    seq,
+		"pad":    pad,
+		"width":  displayWidth,
+		"repeat": repeat,
+		"bar":    bar,
+	}
+
+	for name, function := range templateFuncs {
+		funcs[name] = function
+	}
+
+	tmpl, err := template.New("output").
+		Funcs(funcs).
+		Parse(expandColorDirectives(theme, text))
+	if err != nil {
+		return "", fmt.Errorf("template: %w", err)
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("template: %w", err)
+	}
+
+	return builder.String(), nil
+}
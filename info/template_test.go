@@ -0,0 +1,26 @@
+package info
+
+import "testing"
+
+func TestTemplateValues(t *testing.T) {
+	system := fixtureSystem("debian-12-x86_64")
+	system.Getenv = func(key string) string {
+		if key == "SHELL" {
+			return "/bin/zsh"
+		}
+
+		return ""
+	}
+
+	renderer := &Renderer{System: system}
+	options := map[string]string{"cache": "false", "shell": "true"}
+
+	output, err := renderer.RenderTemplate(options, `{{range values "shell"}}[{{.}}]{{end}} {{value "shell"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "[/bin/zsh] /bin/zsh"; output != want {
+		t.Errorf("got %q, want %q", output, want)
+	}
+}
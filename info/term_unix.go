@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package info
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// winsize is a terminal size, as returned by TIOCGWINSZ
+type winsize struct {
+	Row, Col       uint16
+	Xpixel, Ypixel uint16
+}
+
+// Returns size of terminal on file descriptor
+func getWinsize(fd uintptr) (winsize, error) {
+	var size winsize
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if errno != 0 {
+		return size, errno
+	}
+
+	return size, nil
+}
@@ -0,0 +1,236 @@
+package info
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Theme not found error
+var ErrThemeNotFound = errors.New("not found theme")
+
+// Built-in themes
+//
+//go:embed themes/*
+var themes embed.FS
+
+// Theme is a set of colors and decorations used for output parts, colors
+// are c0-15, named or hex colors, or "accent" for accent color
+type Theme struct {
+	// Accent is an accent color, logo accent color by default
+	Accent string
+
+	// Label is a color of info labels
+	Label string
+
+	// Separator is a color of separator between label and value, and
+	// "@" in title
+	Separator string
+
+	// Value is a color of info values
+	Value string
+
+	// Title is a color of username and hostname
+	Title string
+
+	// Underline is a color of title underline
+	Underline string
+
+	// SeparatorText is a separator between label and value
+	SeparatorText string
+
+	// UnderlineChar is a character used to underline title
+	UnderlineChar string
+
+	// Good, Warn and Crit are colors of percentage bars below warning
+	// threshold, above it and above critical threshold
+	Good, Warn, Crit string
+
+	// depth is a color depth colors are expanded for
+	depth int
+}
+
+// Sets theme field by key, returns false if key is unknown
+func (t *Theme) set(key, value string) bool {
+	switch key {
+	case "accent":
+		t.Accent = value
+	case "label":
+		t.Label = value
+	case "separator":
+		t.Separator = value
+	case "value":
+		t.Value = value
+	case "title":
+		t.Title = value
+	case "underline":
+		t.Underline = value
+	case "separator_text":
+		t.SeparatorText = value
+	case "underline_char":
+		t.UnderlineChar = value
+	case "good":
+		t.Good = value
+	case "warn":
+		t.Warn = value
+	case "crit":
+		t.Crit = value
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Returns color value by name, resolving "accent" and "caccent" to accent,
+// false if color is unknown
+func (t Theme) lookup(color string) (string, bool) {
+	if color == "accent" || color == "caccent" {
+		if t.Accent == "" {
+			return "", true
+		}
+
+		return lookupColor(t.Accent, t.depth)
+	}
+
+	return lookupColor(color, t.depth)
+}
+
+// Returns color value by name, or empty string if color is unknown
+func (t Theme) expand(color string) string {
+	value, _ := t.lookup(color)
+	return value
+}
+
+// Returns text wrapped in color and reset, or text as is if color is empty
+func (t Theme) paint(color, text string) string {
+	escape := t.expand(color)
+	if escape == "" {
+		return text
+	}
+
+	return escape + text + t.expand("creset")
+}
+
+// Parses theme file contents over theme
+func parseTheme(theme *Theme, contents string) error {
+	for i, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("theme: line %v: expected key=value, got %q", i+1, line)
+		}
+
+		if err := setThemeValue(theme, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sets theme key to value of theme file or theme.<key> option, quoted
+// values keep leading and trailing spaces
+func setThemeValue(theme *Theme, key, value string) error {
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return fmt.Errorf("theme %v: %w", key, err)
+		}
+
+		value = unquoted
+	}
+
+	if !theme.set(key, value) {
+		return fmt.Errorf("theme: unknown key %v", key)
+	}
+
+	return nil
+}
+
+// Returns true if theme name is a path to theme file, so themes in current
+// directory don't shadow ones with same name
+func isThemePath(name string) bool {
+	return strings.ContainsRune(name, filepath.Separator) ||
+		strings.HasSuffix(name, ".theme")
+}
+
+// Returns theme file contents by path, or by name searching in
+// $XDG_CONFIG_HOME/barkfetch/themes and built-in themes
+func readTheme(name string) (string, error) {
+	if isThemePath(name) {
+		bytes, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("theme: %w", err)
+		}
+
+		return string(bytes), nil
+	}
+
+	if dir, err := getConfigDir(); err == nil {
+		bytes, err := os.ReadFile(filepath.Join(dir, "themes", name+".theme"))
+		if err == nil {
+			return string(bytes), nil
+		}
+	}
+
+	bytes, err := themes.ReadFile(fmt.Sprintf("themes/%v.theme", name))
+	if err == nil {
+		return string(bytes), nil
+	}
+
+	return "", fmt.Errorf("%w: %v", ErrThemeNotFound, name)
+}
+
+// Returns theme chosen by "theme" option, with "theme.<key>" and "accent"
+// options applied over it, colors are expanded for color depth
+func loadTheme(options map[string]string, depth int) (Theme, error) {
+	theme := Theme{depth: depth}
+
+	// custom themes may omit keys, so they inherit defaults
+	contents, err := readTheme("default")
+	if err != nil {
+		return theme, err
+	}
+
+	if err := parseTheme(&theme, contents); err != nil {
+		return theme, err
+	}
+
+	if name, exists := options["theme"]; exists && name != "default" {
+		contents, err := readTheme(name)
+		if err != nil {
+			return theme, err
+		}
+
+		if err := parseTheme(&theme, contents); err != nil {
+			return theme, err
+		}
+	}
+
+	for key, value := range options {
+		field, found := strings.CutPrefix(key, "theme.")
+		if !found {
+			continue
+		}
+
+		if err := setThemeValue(&theme, field, value); err != nil {
+			return theme, err
+		}
+	}
+
+	if accent, exists := options["accent"]; exists {
+		theme.Accent = accent
+	}
+
+	return theme, nil
+}
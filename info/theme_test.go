@@ -0,0 +1,27 @@
+package info
+
+import "testing"
+
+func TestThemeOptionQuoted(t *testing.T) {
+	tests := map[string]string{
+		`" -> "`: " -> ",
+		`": "`:   ": ",
+		`=`:      "=",
+	}
+
+	for value, want := range tests {
+		theme, err := loadTheme(map[string]string{"theme.separator_text": value}, Depth256)
+		if err != nil {
+			t.Errorf("%q: %v", value, err)
+			continue
+		}
+
+		if theme.SeparatorText != want {
+			t.Errorf("%q: separator is %q, want %q", value, theme.SeparatorText, want)
+		}
+	}
+
+	if _, err := loadTheme(map[string]string{"theme.separator_text": `" -> `}, Depth256); err == nil {
+		t.Errorf("unterminated quote is accepted")
+	}
+}
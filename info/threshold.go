@@ -0,0 +1,102 @@
+package info
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alert thresholds of module value, read from <module>.warn and
+// <module>.crit options, or <module>.<name>.warn and <module>.<name>.crit
+// for named values, like "disk./.crit"
+type thresholds struct {
+	warn, crit       int64
+	hasWarn, hasCrit bool
+
+	// below is true if value is bad when it's low, like battery charge
+	below bool
+}
+
+// Reads thresholds of module value from options, named value thresholds
+// override module ones
+func getThresholds(options map[string]string, module, name string) (thresholds, error) {
+	limits := thresholds{below: module == "battery"}
+
+	for _, limit := range []struct {
+		key   string
+		value *int64
+		has   *bool
+	}{{"warn", &limits.warn, &limits.hasWarn}, {"crit", &limits.crit, &limits.hasCrit}} {
+		keys := []string{module + "." + limit.key}
+		if name != "" {
+			keys = append([]string{module + "." + name + "." + limit.key}, keys...)
+		}
+
+		for _, key := range keys {
+			value, exists := options[key]
+			if !exists || value == "" {
+				continue
+			}
+
+			threshold, err := parseThreshold(module, value)
+			if err != nil {
+				return limits, fmt.Errorf("%w: %v=%v", ErrInvalidOption, key, value)
+			}
+
+			*limit.value, *limit.has = threshold, true
+			break
+		}
+	}
+
+	return limits, nil
+}
+
+// Parses threshold: duration like "30d", "12h" or "90m" for uptime, or
+// percentage like "90" or "90%" for other modules
+func parseThreshold(module, value string) (int64, error) {
+	if module != "uptime" {
+		return strconv.ParseInt(strings.TrimSuffix(value, "%"), 10, 64)
+	}
+
+	if days, found := strings.CutSuffix(value, "d"); found {
+		count, err := strconv.ParseInt(days, 10, 64)
+		return count * 86400, err
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		// plain number is seconds
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	return int64(duration.Seconds()), nil
+}
+
+// Returns true if any threshold is set
+func (t thresholds) set() bool {
+	return t.hasWarn || t.hasCrit
+}
+
+// Returns true if level reached threshold
+func (t thresholds) reached(level, threshold int64) bool {
+	if t.below {
+		return level <= threshold
+	}
+
+	return level >= threshold
+}
+
+// Returns critical or warning color of theme if level reached threshold,
+// or empty string if it didn't
+func (t thresholds) color(theme Theme, level int64) string {
+	switch {
+	case t.hasCrit && t.reached(level, t.crit):
+		return theme.Crit
+
+	case t.hasWarn && t.reached(level, t.warn):
+		return theme.Warn
+	}
+
+	return ""
+}
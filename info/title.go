@@ -0,0 +1,122 @@
+package info
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Regex matching {name} placeholders of title template
+var titlePlaceholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// Default title template
+const defaultTitle = "{user}@{host}"
+
+// Returns title template from title option
+func titleTemplate(options map[string]string) string {
+	title, exists := options["title"]
+	if !exists || title == "" {
+		return defaultTitle
+	}
+
+	return title
+}
+
+// Returns true if title template uses any of placeholders, so it's data
+// has to be probed
+func titleUses(options map[string]string, placeholders ...string) bool {
+	for _, match := range titlePlaceholderRegex.FindAllStringSubmatch(titleTemplate(options), -1) {
+		for _, placeholder := range placeholders {
+			if match[1] == placeholder {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns value of title placeholder, false if placeholder is unknown
+func titleValue(info Info, placeholder string) (string, bool) {
+	fqdn := info.FQDN
+	if fqdn == "" {
+		fqdn = info.Host
+	}
+
+	switch placeholder {
+	case "user":
+		return info.User, true
+
+	case "host":
+		return info.Host, true
+
+	case "short":
+		short, _, _ := strings.Cut(info.Host, ".")
+		return short, true
+
+	case "fqdn":
+		return fqdn, true
+
+	case "domain":
+		_, domain, _ := strings.Cut(fqdn, ".")
+		return domain, true
+
+	case "ip":
+		if info.LocalIP == "" {
+			return "n/a", true
+		}
+
+		return info.LocalIP, true
+
+	case "name":
+		return info.UserName, true
+
+	case "real_user":
+		return info.RealUser, true
+	}
+
+	return "", false
+}
+
+// Returns title rendered from title template, like "user@host".
+// Placeholders are painted with title color and text between them with
+// separator color
+func titleLine(options map[string]string, info Info, theme Theme) (string, error) {
+	text := titleTemplate(options)
+
+	var title string
+	last := 0
+
+	for _, match := range titlePlaceholderRegex.FindAllStringSubmatchIndex(text, -1) {
+		placeholder := text[match[2]:match[3]]
+
+		value, known := titleValue(info, placeholder)
+		if !known {
+			return "", fmt.Errorf("%w: title: unknown placeholder {%v}", ErrInvalidOption, placeholder)
+		}
+
+		if match[0] > last {
+			title += theme.paint(theme.Separator, text[last:match[0]])
+		}
+
+		title += theme.paint(theme.Title, value)
+		last = match[1]
+	}
+
+	if last < len(text) {
+		title += theme.paint(theme.Separator, text[last:])
+	}
+
+	return title, nil
+}
+
+// Returns underline of title, underline character repeated to title
+// display width
+func underlineLine(title string, theme Theme) string {
+	count := displayWidth(title)
+	if width := displayWidth(theme.UnderlineChar); width > 1 {
+		count /= width
+	}
+
+	return theme.paint(theme.Underline, strings.Repeat(theme.UnderlineChar, count))
+}
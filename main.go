@@ -1,10 +1,16 @@
 package main
 
-import "github.com/xbt573/barkfetch/cmd"
+import (
+	"fmt"
+	"os"
+
+	"github.com/xbt573/barkfetch/cmd"
+)
 
 func main() {
 	err := cmd.Run()
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "barkfetch: %v\n", err)
+		os.Exit(1)
 	}
 }